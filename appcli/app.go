@@ -1,6 +1,7 @@
 package appcli
 
 import (
+	"fmt"
 	"io/ioutil"
 	"os"
 	"path/filepath"
@@ -27,8 +28,26 @@ func newBaseApp() *cli.App {
 			Usage: "Show help and exit",
 		},
 		cli.StringFlag{
-			Name:  "f, file",
-			Usage: "Set `file` to use as the config file",
+			Name:   "f, file",
+			Usage:  "Set `file` to use as the config file",
+			EnvVar: "TUSK_FILE",
+		},
+		cli.StringFlag{
+			Name:  "events",
+			Usage: "Write JSON events to `file` alongside normal output",
+		},
+		cli.StringFlag{
+			Name:  "log-format",
+			Usage: "Set the `format` of tusk output to text or json",
+			Value: string(ui.LogFormatText),
+		},
+		cli.BoolFlag{
+			Name:  "prefix",
+			Usage: "Prefix each line of command output with the task name",
+		},
+		cli.BoolFlag{
+			Name:  "strict",
+			Usage: "Fail when an undefined option is referenced",
 		},
 		cli.BoolFlag{
 			Name:  "q, quiet",
@@ -46,6 +65,10 @@ func newBaseApp() *cli.App {
 			Name:  "V, version",
 			Usage: "Print version and exit",
 		},
+		cli.BoolFlag{
+			Name:  "w, watch",
+			Usage: "Rerun the task when the files it watches change",
+		},
 	)
 
 	sort.Sort(flagsByName(app.Flags))
@@ -62,8 +85,8 @@ func newSilentApp() *cli.App {
 }
 
 // newFlagApp creates a cli.App that can parse flags.
-func newFlagApp(cfgText []byte) (*cli.App, error) {
-	cfg, err := config.Parse(cfgText)
+func newFlagApp(meta *config.Metadata) (*cli.App, error) {
+	cfg, err := meta.Parse()
 	if err != nil {
 		return nil, err
 	}
@@ -71,19 +94,31 @@ func newFlagApp(cfgText []byte) (*cli.App, error) {
 	app := newSilentApp()
 	app.Metadata = make(map[string]interface{})
 	app.Metadata["flagsPassed"] = make(map[string]string)
+	app.Action = func(c *cli.Context) error {
+		app.Metadata["noArgs"] = !c.Args().Present()
+		return nil
+	}
 
 	if err = addTasks(app, cfg, createMetadataBuildCommand); err != nil {
 		return nil, err
 	}
 
+	if err = addExplainCommand(app, cfg, false); err != nil {
+		return nil, err
+	}
+
 	return app, nil
 }
 
 // NewApp creates a cli.App that executes tasks.
 func NewApp(meta *config.Metadata) (*cli.App, error) {
-	flagApp, err := newFlagApp(meta.CfgText)
+	if app, ok := newBuiltinApp(meta, os.Args); ok {
+		return app, nil
+	}
+
+	flagApp, err := newFlagApp(meta)
 	if err != nil {
-		return nil, err
+		return nil, config.WithFile(err, meta.CfgPath)
 	}
 
 	if err = flagApp.Run(os.Args); err != nil {
@@ -95,18 +130,34 @@ func NewApp(meta *config.Metadata) (*cli.App, error) {
 		return nil, errors.New("could not read flags from metadata")
 	}
 
+	cfg, err := meta.Parse()
+	if err != nil {
+		return nil, config.WithFile(err, meta.CfgPath)
+	}
+
+	setLocalValues(cfg, meta)
+
+	for _, t := range cfg.Tasks {
+		t.Strict = meta.Strict
+	}
+
 	var taskName string
 	command, ok := flagApp.Metadata["command"].(*cli.Command)
 	if ok {
 		taskName = command.Name
 	}
 
-	cfgText, flags, err := config.Interpolate(meta.CfgText, passed, taskName)
-	if err != nil {
-		return nil, err
+	defaultTask, hasDefault := cfg.FindTask(cfg.Default)
+	if noArgs, _ := flagApp.Metadata["noArgs"].(bool); noArgs && hasDefault {
+		taskName = defaultTask.Name
+	}
+
+	interpolate := config.Interpolate
+	if explain, _ := flagApp.Metadata["explain"].(bool); explain {
+		interpolate = config.Resolve
 	}
 
-	cfg, err := config.Parse(cfgText)
+	flags, err := interpolate(cfg, passed, taskName)
 	if err != nil {
 		return nil, err
 	}
@@ -116,16 +167,24 @@ func NewApp(meta *config.Metadata) (*cli.App, error) {
 	}
 
 	app := newBaseApp()
+	app.Metadata = map[string]interface{}{"watch": meta.Watch}
 
 	if err := addTasks(app, cfg, createExecuteCommand); err != nil {
 		return nil, err
 	}
 
 	copyFlags(app, flagApp)
+	if err := addExplainCommand(app, cfg, true); err != nil {
+		return nil, err
+	}
+	addBuiltinCommands(app, cfg, meta)
 
-	app.BashComplete = createDefaultComplete(app)
-	for i := range app.Commands {
-		app.Commands[i].BashComplete = createCommandComplete(&app.Commands[i])
+	if hasDefault {
+		app.Action = createDefaultAction(defaultTask, meta.Watch)
+	}
+
+	if err := addCompletions(app, cfg); err != nil {
+		return nil, err
 	}
 
 	return app, nil
@@ -162,9 +221,39 @@ func GetConfigMetadata(args []string) (*config.Metadata, error) {
 			}
 		}
 
-		metadata.Directory = filepath.Dir(fullPath)
+		metadata.CfgPath = fullPath
+		if metadata.Directory, err = filepath.Abs(filepath.Dir(fullPath)); err != nil {
+			return nil
+		}
+
+		if metadata.Parents, err = config.FindParents(fullPath, metadata.CfgText); err != nil {
+			return nil
+		}
+
+		if err = addUserFile(metadata); err != nil {
+			return nil
+		}
+
+		metadata.LocalPath, metadata.LocalValues, err = config.FindLocalValues(metadata.Directory)
+		if err != nil {
+			return nil
+		}
+
 		metadata.PrintHelp = c.Bool("help")
 		metadata.PrintVersion = c.Bool("version")
+		metadata.PrefixOutput = c.Bool("prefix")
+		metadata.Strict = c.Bool("strict")
+		metadata.Watch = c.Bool("watch")
+
+		if metadata.LogFormat, err = getLogFormat(c.String("log-format")); err != nil {
+			return nil
+		}
+
+		if eventsPath := c.String("events"); eventsPath != "" {
+			if metadata.EventsFile, err = filepath.Abs(eventsPath); err != nil {
+				return nil
+			}
+		}
 
 		if c.Bool("silent") {
 			metadata.Verbosity = ui.VerbosityLevelSilent
@@ -186,6 +275,66 @@ func GetConfigMetadata(args []string) (*config.Metadata, error) {
 	return metadata, err
 }
 
+// setLocalValues sets the option values from the local values file.
+func setLocalValues(cfg *config.Config, meta *config.Metadata) {
+	if meta.LocalPath == "" {
+		return
+	}
+
+	ui.Debug(fmt.Sprintf("Using option values from %s", meta.LocalPath))
+	for _, name := range cfg.SetLocalValues(meta.LocalValues) {
+		ui.Warn(fmt.Sprintf(`%s sets a value for undefined option "%s"`, meta.LocalPath, name))
+	}
+}
+
+// addUserFile adds the user config file as the lowest priority parent, unless
+// it is already being used.
+func addUserFile(meta *config.Metadata) error {
+	user, found, err := config.FindUserFile()
+	if err != nil || !found {
+		return err
+	}
+
+	paths := []string{meta.CfgPath}
+	for _, parent := range meta.Parents {
+		paths = append(paths, parent.Path)
+	}
+
+	for _, path := range paths {
+		if isSameFile(path, user.Path) {
+			return nil
+		}
+	}
+
+	meta.Parents = append(meta.Parents, user)
+	return nil
+}
+
+// isSameFile tells if two paths refer to the same existing file.
+func isSameFile(a, b string) bool {
+	aInfo, err := os.Stat(a)
+	if err != nil {
+		return false
+	}
+
+	bInfo, err := os.Stat(b)
+	if err != nil {
+		return false
+	}
+
+	return os.SameFile(aInfo, bInfo)
+}
+
+// getLogFormat validates the log format passed by command line.
+func getLogFormat(format string) (ui.LogFormat, error) {
+	switch f := ui.LogFormat(format); f {
+	case ui.LogFormatText, ui.LogFormatJSON:
+		return f, nil
+	default:
+		return "", fmt.Errorf(`unsupported log format "%s"`, format)
+	}
+}
+
 // populateMetadata runs the app to populate the metadata struct.
 func populateMetadata(app *cli.App, args []string) error {
 	args = removeCompletionArg(args)
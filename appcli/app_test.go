@@ -8,6 +8,7 @@ import (
 	"reflect"
 	"testing"
 
+	"github.com/rliebz/tusk/config"
 	"github.com/rliebz/tusk/ui"
 	"github.com/urfave/cli"
 )
@@ -23,7 +24,7 @@ tasks:
     run: echo ${foo}
 `)
 
-	flagApp, err := newFlagApp(cfgText)
+	flagApp, err := newFlagApp(&config.Metadata{CfgText: cfgText})
 	if err != nil {
 		t.Fatalf(
 			"newFlagApp():\nconfig: `%s`\nunexpected err: %s",
@@ -83,7 +84,7 @@ func TestNewFlagApp_no_options(t *testing.T) {
     run: echo foo
 `)
 
-	flagApp, err := newFlagApp(cfgText)
+	flagApp, err := newFlagApp(&config.Metadata{CfgText: cfgText})
 	if err != nil {
 		t.Fatalf(
 			"newFlagApp():\nconfig: `%s`\nunexpected err: %s",
@@ -189,7 +190,10 @@ func TestGetConfigMetadata_file(t *testing.T) {
 		)
 	}
 
-	directory := "testdata"
+	directory, err := filepath.Abs("testdata")
+	if err != nil {
+		t.Fatalf("filepath.Abs(testdata): unexpected err: %s", err)
+	}
 
 	if directory != metadata.Directory {
 		t.Errorf(
@@ -229,6 +233,35 @@ func TestGetConfigMetadata_fileNoExist(t *testing.T) {
 	}
 }
 
+func TestGetConfigMetadata_fileEnvironment(t *testing.T) {
+	cfgPath := "testdata/example.yml"
+	if err := os.Setenv("TUSK_FILE", cfgPath); err != nil {
+		t.Fatalf("unexpected error setting TUSK_FILE: %s", err)
+	}
+	defer os.Unsetenv("TUSK_FILE") // nolint: errcheck
+
+	args := []string{"tusk"}
+	metadata, err := GetConfigMetadata(args)
+	if err != nil {
+		t.Fatalf("GetConfigMetadata(%s): unexpected err: %s", args, err)
+	}
+
+	if metadata.CfgPath != cfgPath {
+		t.Errorf(
+			"GetConfigMetadata(%s): expected CfgPath: %s, actual: %s",
+			args, cfgPath, metadata.CfgPath,
+		)
+	}
+
+	args = []string{"tusk", "--file", "fakefile.yml"}
+	if _, err := GetConfigMetadata(args); !os.IsNotExist(err) {
+		t.Errorf(
+			"GetConfigMetadata(%s): expected --file to take precedence, actual err: %v",
+			args, err,
+		)
+	}
+}
+
 func TestGetConfigMetadata_version(t *testing.T) {
 	args := []string{"tusk", "--version"}
 
@@ -248,6 +281,87 @@ func TestGetConfigMetadata_version(t *testing.T) {
 	}
 }
 
+func TestGetConfigMetadata_prefix(t *testing.T) {
+	args := []string{"tusk", "--prefix"}
+
+	metadata, err := GetConfigMetadata(args)
+	if err != nil {
+		t.Fatalf(
+			"GetConfigMetadata(%s):\nunexpected err: %s",
+			args, err,
+		)
+	}
+
+	if !metadata.PrefixOutput {
+		t.Errorf(
+			"GetConfigMetadata(%s): expected PrefixOutput: true, actual: false",
+			args,
+		)
+	}
+}
+
+func TestGetConfigMetadata_strict(t *testing.T) {
+	args := []string{"tusk", "--strict"}
+
+	metadata, err := GetConfigMetadata(args)
+	if err != nil {
+		t.Fatalf(
+			"GetConfigMetadata(%s):\nunexpected err: %s",
+			args, err,
+		)
+	}
+
+	if !metadata.Strict {
+		t.Errorf(
+			"GetConfigMetadata(%s): expected Strict: true, actual: false",
+			args,
+		)
+	}
+}
+
+func TestGetConfigMetadata_watch(t *testing.T) {
+	args := []string{"tusk", "--watch"}
+
+	metadata, err := GetConfigMetadata(args)
+	if err != nil {
+		t.Fatalf(
+			"GetConfigMetadata(%s):\nunexpected err: %s",
+			args, err,
+		)
+	}
+
+	if !metadata.Watch {
+		t.Errorf(
+			"GetConfigMetadata(%s): expected Watch: true, actual: false",
+			args,
+		)
+	}
+}
+
+func TestGetConfigMetadata_logFormat(t *testing.T) {
+	args := []string{"tusk", "--log-format", "json"}
+
+	metadata, err := GetConfigMetadata(args)
+	if err != nil {
+		t.Fatalf(
+			"GetConfigMetadata(%s):\nunexpected err: %s",
+			args, err,
+		)
+	}
+
+	if metadata.LogFormat != ui.LogFormatJSON {
+		t.Errorf(
+			"GetConfigMetadata(%s): expected LogFormat: %s, actual: %s",
+			args, ui.LogFormatJSON, metadata.LogFormat,
+		)
+	}
+
+	args = []string{"tusk", "--log-format", "xml"}
+	if _, err := GetConfigMetadata(args); err == nil {
+		t.Errorf("GetConfigMetadata(%s): expected err, actual nil", args)
+	}
+}
+
 var verbosityFlagTests = []struct {
 	args     []string
 	expected ui.VerbosityLevel
@@ -0,0 +1,246 @@
+package appcli
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/urfave/cli"
+
+	"github.com/rliebz/tusk/config"
+	"github.com/rliebz/tusk/ui"
+)
+
+// builtinMetadataKey is the key of the app metadata that lists the names of
+// the built-in commands added to an app.
+const builtinMetadataKey = "builtins"
+
+// builtinCommands returns the commands provided by tusk itself rather than
+// by the config file. Built-in commands are hidden from the list of tasks and
+// listed separately in help messages, and a task with the same name always
+// takes precedence.
+func builtinCommands(meta *config.Metadata) []cli.Command {
+	return []cli.Command{
+		{
+			Name:      "completion",
+			Usage:     "Print the shell completion script",
+			ArgsUsage: "<bash|zsh|fish>",
+			Action:    completionAction,
+			Flags: []cli.Flag{
+				cli.BoolFlag{
+					Name:  "install",
+					Usage: "Load completion from the shell config instead of printing",
+				},
+			},
+		},
+		{
+			Name:      "graph",
+			Usage:     "Print the graph of tasks and the options they use",
+			ArgsUsage: "[task]",
+			Action:    createGraphAction(meta),
+			Flags: []cli.Flag{
+				cli.StringFlag{
+					Name:  "format",
+					Usage: "Set the `format` of the graph to dot or mermaid",
+					Value: "dot",
+				},
+			},
+		},
+		{
+			Name:   "schema",
+			Usage:  "Print the JSON Schema for config files",
+			Action: schemaAction,
+		},
+		{
+			Name:   "validate",
+			Usage:  "Check the config file for problems",
+			Action: createValidateAction(meta),
+		},
+	}
+}
+
+// addBuiltinCommands adds every built-in command not overridden by a task.
+func addBuiltinCommands(app *cli.App, cfg *config.Config, meta *config.Metadata) {
+	for _, command := range builtinCommands(meta) {
+		if _, ok := cfg.FindTask(command.Name); !ok {
+			addBuiltinCommand(app, command)
+		}
+	}
+}
+
+// addBuiltinCommand adds a built-in command to an app. The command is hidden
+// from the list of tasks and recorded in the app metadata instead.
+func addBuiltinCommand(app *cli.App, command cli.Command) {
+	command.Hidden = true
+	app.Commands = append(app.Commands, command)
+
+	if app.Metadata == nil {
+		app.Metadata = make(map[string]interface{})
+	}
+	names, _ := app.Metadata[builtinMetadataKey].([]string)
+	app.Metadata[builtinMetadataKey] = append(names, command.Name)
+}
+
+// builtinsOf returns the built-in commands of an app.
+func builtinsOf(app *cli.App) []cli.Command {
+	names, _ := app.Metadata[builtinMetadataKey].([]string)
+
+	var commands []cli.Command
+	for _, name := range names {
+		if command := app.Command(name); command != nil {
+			commands = append(commands, *command)
+		}
+	}
+
+	return commands
+}
+
+// newBuiltinApp creates a cli.App for running a built-in command. Built-in
+// commands run without building the tasks first, so that they still work
+// when the config file is invalid. If the command invoked is not built-in,
+// false is returned instead.
+func newBuiltinApp(meta *config.Metadata, args []string) (*cli.App, bool) {
+	var invoked string
+
+	finder := newSilentApp()
+	for _, command := range builtinCommands(meta) {
+		name := command.Name
+		command.Action = func(c *cli.Context) error {
+			invoked = name
+			return nil
+		}
+		command.SkipFlagParsing = true
+		finder.Commands = append(finder.Commands, command)
+	}
+
+	if err := finder.Run(removeCompletionArg(args)); err != nil || invoked == "" {
+		return nil, false
+	}
+
+	if cfg, err := meta.Parse(); err == nil {
+		if _, ok := cfg.FindTask(invoked); ok {
+			return nil, false
+		}
+	}
+
+	app := newBaseApp()
+	for _, command := range builtinCommands(meta) {
+		addBuiltinCommand(app, command)
+	}
+	return app, true
+}
+
+// completionAction prints or installs the completion script for a shell.
+func completionAction(c *cli.Context) error {
+	if c.NArg() != 1 {
+		return errors.New("exactly one shell must be passed: bash, zsh, or fish")
+	}
+
+	shell := c.Args().First()
+	script, ok := completionScripts[shell]
+	if !ok {
+		return fmt.Errorf("unsupported shell: %s", shell)
+	}
+
+	if !c.Bool("install") {
+		ui.Println(strings.TrimSuffix(script, "\n"))
+		return nil
+	}
+
+	path, changed, err := installCompletion(shell)
+	if err != nil {
+		return err
+	}
+
+	if !changed {
+		ui.Info(fmt.Sprintf("Completion is already installed in %s", path))
+		return nil
+	}
+
+	ui.Info(fmt.Sprintf("Installed completion in %s, restart the shell to use it", path))
+	return nil
+}
+
+// createGraphAction prints the graph of one task, or every task if none is
+// passed.
+func createGraphAction(meta *config.Metadata) func(c *cli.Context) error {
+	return func(c *cli.Context) error {
+		if c.NArg() > 1 {
+			return fmt.Errorf("unexpected argument: %s", c.Args().Get(1))
+		}
+
+		cfg, err := meta.Parse()
+		if err != nil {
+			return config.WithFile(err, meta.CfgPath)
+		}
+
+		graph, err := cfg.Graph(c.Args().First())
+		if err != nil {
+			return err
+		}
+
+		switch format := c.String("format"); format {
+		case "dot":
+			ui.Println(strings.TrimSuffix(graph.DOT(), "\n"))
+		case "mermaid":
+			ui.Println(strings.TrimSuffix(graph.Mermaid(), "\n"))
+		default:
+			return fmt.Errorf(`unsupported graph format "%s"`, format)
+		}
+
+		return nil
+	}
+}
+
+// schemaAction prints the JSON Schema for config files.
+func schemaAction(c *cli.Context) error {
+	if c.Args().Present() {
+		return fmt.Errorf("unexpected argument: %s", c.Args().First())
+	}
+
+	schema, err := json.MarshalIndent(config.Schema(), "", "  ")
+	if err != nil {
+		return err
+	}
+
+	ui.Println(string(schema))
+	return nil
+}
+
+// createValidateAction prints every problem found in the config file.
+func createValidateAction(meta *config.Metadata) func(c *cli.Context) error {
+	return func(c *cli.Context) error {
+		if c.Args().Present() {
+			return fmt.Errorf("unexpected argument: %s", c.Args().First())
+		}
+
+		problems, err := meta.Validate()
+		if err != nil {
+			return config.WithFile(err, meta.CfgPath)
+		}
+
+		for _, problem := range problems {
+			ui.Println(formatProblem(meta.CfgPath, problem))
+		}
+
+		if len(problems) > 0 {
+			return fmt.Errorf("found %d problem(s) in config file", len(problems))
+		}
+
+		ui.Info("Config file is valid")
+		return nil
+	}
+}
+
+// formatProblem formats a problem in the style of compiler output.
+func formatProblem(cfgPath string, problem config.Problem) string {
+	if cfgPath == "" || problem.Line == 0 {
+		return problem.String()
+	}
+
+	return fmt.Sprintf(
+		"%s:%d:%d: %s: %s",
+		cfgPath, problem.Line, problem.Column, problem.Path, problem.Message,
+	)
+}
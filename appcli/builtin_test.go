@@ -0,0 +1,97 @@
+package appcli
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/rliebz/tusk/config"
+)
+
+var builtinapptests = []struct {
+	desc     string
+	cfgText  string
+	args     []string
+	expected bool
+}{
+	{"builtin", "tasks: {}", []string{"tusk", "validate"}, true},
+	{"builtin with global flags", "", []string{"tusk", "-q", "validate"}, true},
+	{"invalid config", "tasks: {foo: {bar: baz}}", []string{"tusk", "validate"}, true},
+	{"graph", "tasks: {foo: {}}", []string{"tusk", "graph", "foo"}, true},
+	{"task", "tasks: {foo: {}}", []string{"tusk", "foo"}, false},
+	{"overridden by task", "tasks: {validate: {}}", []string{"tusk", "validate"}, false},
+	{"no command", "", []string{"tusk"}, false},
+}
+
+func TestNewBuiltinApp(t *testing.T) {
+	for _, tt := range builtinapptests {
+		meta := &config.Metadata{CfgText: []byte(tt.cfgText)}
+		app, actual := newBuiltinApp(meta, tt.args)
+		if tt.expected != actual {
+			t.Errorf(
+				"newBuiltinApp() for %s: expected %t, actual %t",
+				tt.desc, tt.expected, actual,
+			)
+			continue
+		}
+
+		if actual && len(app.Commands) == 0 {
+			t.Errorf("newBuiltinApp() for %s: expected commands, got none", tt.desc)
+		}
+	}
+}
+
+func TestAddBuiltinCommands(t *testing.T) {
+	meta := &config.Metadata{CfgText: []byte("tasks: {validate: {}, build: {}}")}
+	cfg, err := meta.Parse()
+	if err != nil {
+		t.Fatalf("meta.Parse(): unexpected err: %s", err)
+	}
+
+	app := newBaseApp()
+	if err = addTasks(app, cfg, createExecuteCommand); err != nil {
+		t.Fatalf("addTasks(): unexpected err: %s", err)
+	}
+	addBuiltinCommands(app, cfg, meta)
+
+	var names []string
+	for _, command := range builtinsOf(app) {
+		if !command.Hidden {
+			t.Errorf(`addBuiltinCommands(): expected command "%s" to be hidden from the tasks`, command.Name)
+		}
+		names = append(names, command.Name)
+	}
+
+	if expected := []string{"completion", "graph", "schema"}; !reflect.DeepEqual(expected, names) {
+		t.Errorf("addBuiltinCommands(): expected built-in commands %v, actual %v", expected, names)
+	}
+
+	var buf bytes.Buffer
+	app.Writer = &buf
+	ShowAppHelp(app)
+
+	help := buf.String()
+	i := strings.Index(help, "Commands:")
+	if i < 0 {
+		t.Fatalf("ShowAppHelp(): expected a list of commands, actual:\n%s", help)
+	}
+
+	if tasks := help[:i]; strings.Contains(tasks, "graph") || !strings.Contains(tasks, "validate") {
+		t.Errorf("ShowAppHelp(): expected only tasks before the commands, actual:\n%s", help)
+	}
+}
+
+func TestFormatProblem(t *testing.T) {
+	problem := config.Problem{Path: "tasks.foo", Line: 3, Column: 5, Message: "oops"}
+
+	expected := "tusk.yml:3:5: tasks.foo: oops"
+	if actual := formatProblem("tusk.yml", problem); expected != actual {
+		t.Errorf("formatProblem(): expected %q, actual %q", expected, actual)
+	}
+
+	expected = "line 3, column 5: tasks.foo: oops"
+	if actual := formatProblem("", problem); expected != actual {
+		t.Errorf("formatProblem(): expected %q, actual %q", expected, actual)
+	}
+}
@@ -8,19 +8,50 @@ import (
 	"github.com/urfave/cli"
 
 	"github.com/rliebz/tusk/config/task"
+	"github.com/rliebz/tusk/interp"
+	"github.com/rliebz/tusk/ui"
 )
 
 type commandCreator func(app *cli.App, t *task.Task) (*cli.Command, error)
 
 func createExecuteCommand(app *cli.App, t *task.Task) (*cli.Command, error) {
+	watch, _ := app.Metadata["watch"].(bool)
+
 	return createCommand(t, func(c *cli.Context) error {
 		if c.Args().Present() {
 			return fmt.Errorf("unexpected argument: %s", c.Args().First())
 		}
-		return t.Execute()
+		if t.Private {
+			return fmt.Errorf(`task "%s" is private and can only be run as a sub-task`, t.Name)
+		}
+		if watch {
+			return watchTask(t)
+		}
+		return executeTask(t)
 	}), nil
 }
 
+// createDefaultAction creates the action to run a task when no task is passed.
+// Any other arguments are handled the same way as with no default task.
+func createDefaultAction(t *task.Task, watch bool) func(c *cli.Context) error {
+	return func(c *cli.Context) error {
+		if c.Args().Present() {
+			return cli.ShowCommandHelp(c, c.Args().First())
+		}
+		if watch {
+			return watchTask(t)
+		}
+		return executeTask(t)
+	}
+}
+
+// executeTask runs a task and prints the summary.
+func executeTask(t *task.Task) error {
+	err := t.Execute()
+	ui.PrintSummary()
+	return err
+}
+
 func createMetadataBuildCommand(app *cli.App, t *task.Task) (*cli.Command, error) {
 	passed, ok := app.Metadata["flagsPassed"].(map[string]string)
 	if !ok {
@@ -38,12 +69,38 @@ func createMetadataBuildCommand(app *cli.App, t *task.Task) (*cli.Command, error
 	}), nil
 }
 
+// category returns the category of a task in help messages. Tasks without a
+// category are grouped by namespace, which is the part of the name before the
+// last colon, so that db:migrate and db:seed are both listed under db.
+func category(t *task.Task) string {
+	if t.Category != "" {
+		return t.Category
+	}
+
+	if i := strings.LastIndex(t.Name, ":"); i > 0 {
+		return t.Name[:i]
+	}
+
+	return ""
+}
+
+// userConfigLabel marks tasks from the user config file in help messages.
+const userConfigLabel = "(user config)"
+
 // createCommand creates a cli.Command from a task.Task.
 func createCommand(t *task.Task, actionFunc func(*cli.Context) error) *cli.Command {
+	usage := strings.TrimSpace(string(interp.Escape([]byte(t.Usage))))
+	if t.User {
+		usage = strings.TrimSpace(usage + " " + userConfigLabel)
+	}
+
 	return &cli.Command{
 		Name:        t.Name,
-		Usage:       strings.TrimSpace(t.Usage),
-		Description: strings.TrimSpace(t.Description),
+		Usage:       usage,
+		Description: strings.TrimSpace(string(interp.Escape([]byte(t.Description)))),
+		Aliases:     t.Aliases,
+		Category:    category(t),
+		Hidden:      t.Private,
 		Action:      actionFunc,
 	}
 }
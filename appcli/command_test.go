@@ -0,0 +1,67 @@
+package appcli
+
+import (
+	"testing"
+
+	"github.com/urfave/cli"
+
+	"github.com/rliebz/tusk/config/task"
+)
+
+func TestCreateExecuteCommand_private(t *testing.T) {
+	app := newSilentApp()
+	command, err := createExecuteCommand(app, &task.Task{Name: "private", Private: true})
+	if err != nil {
+		t.Fatalf("createExecuteCommand(): unexpected error: %s", err)
+	}
+
+	if !command.Hidden {
+		t.Error("createExecuteCommand(): expected private task to be hidden")
+	}
+
+	app.Commands = []cli.Command{*command}
+	if err := app.Run([]string{"tusk", "private"}); err == nil {
+		t.Error("app.Run(private): expected error, actual nil")
+	}
+}
+
+var usagetests = []struct {
+	task     task.Task
+	expected string
+}{
+	{task.Task{Usage: "Build it"}, "Build it"},
+	{task.Task{Usage: "Build it", User: true}, "Build it (user config)"},
+	{task.Task{User: true}, "(user config)"},
+}
+
+func TestCreateCommand_usage(t *testing.T) {
+	for _, tt := range usagetests {
+		command := createCommand(&tt.task, nil)
+		if tt.expected != command.Usage {
+			t.Errorf("createCommand(): expected usage %q, actual %q", tt.expected, command.Usage)
+		}
+	}
+}
+
+var categorytests = []struct {
+	task     task.Task
+	expected string
+}{
+	{task.Task{Name: "test"}, ""},
+	{task.Task{Name: "db:migrate"}, "db"},
+	{task.Task{Name: "db:migrate:up"}, "db:migrate"},
+	{task.Task{Name: "db:migrate", Category: "Database"}, "Database"},
+	{task.Task{Name: ":odd"}, ""},
+}
+
+func TestCreateCommand_category(t *testing.T) {
+	for _, tt := range categorytests {
+		command := createCommand(&tt.task, nil)
+		if tt.expected != command.Category {
+			t.Errorf(
+				"createCommand() for %s: expected category %q, actual %q",
+				tt.task.Name, tt.expected, command.Category,
+			)
+		}
+	}
+}
@@ -6,6 +6,9 @@ import (
 	"strings"
 
 	"github.com/urfave/cli"
+
+	"github.com/rliebz/tusk/config"
+	"github.com/rliebz/tusk/config/option"
 )
 
 // CompletionFlag is the flag passed when performing shell completions.
@@ -39,12 +42,15 @@ func createDefaultComplete(app *cli.App) func(c *cli.Context) {
 
 // createCommandComplete prints the completion metadata for a cli command.
 // The metadata includes the completion type followed by a list of options.
-// The available completion types are "normal" and "file". Normal will return
-// task-specific flags, while file allows completion engines to use system files.
-func createCommandComplete(command *cli.Command) func(c *cli.Context) {
+// The available completion types are "normal", "file", "directory", and
+// "values". Normal will return task-specific flags, file and directory allow
+// completion engines to use system files, and values will return the values
+// suggested for the option being completed.
+func createCommandComplete(command *cli.Command, options []*option.Option) func(c *cli.Context) {
 	return func(c *cli.Context) {
 
-		if !isCompletingFlag(command.Flags, os.Args[len(os.Args)-2]) {
+		arg := os.Args[len(os.Args)-2]
+		if !isCompletingFlag(command.Flags, arg) {
 			fmt.Println("normal")
 			for _, flag := range command.Flags {
 				printFlag(c, flag)
@@ -52,18 +58,72 @@ func createCommandComplete(command *cli.Command) func(c *cli.Context) {
 			return
 		}
 
-		// Default to file completion
+		printOptionValues(findOption(options, strings.TrimLeft(arg, "-")))
+	}
+}
+
+// addCompletions sets the completion functions for an app and its commands.
+func addCompletions(app *cli.App, cfg *config.Config) error {
+	app.BashComplete = createDefaultComplete(app)
+
+	for i := range app.Commands {
+		var options []*option.Option
+		if t, ok := cfg.Tasks[app.Commands[i].Name]; ok {
+			var err error
+			if options, err = cfg.FindAllOptions(t); err != nil {
+				return err
+			}
+		}
+
+		app.Commands[i].BashComplete = createCommandComplete(&app.Commands[i], options)
+	}
+
+	return nil
+}
+
+// printOptionValues prints the completion metadata for the value of an
+// option, defaulting to file completion.
+func printOptionValues(opt *option.Option) {
+	switch {
+	case opt == nil:
+		fmt.Println("file")
+	case opt.Complete != nil && opt.Complete.Directories:
+		fmt.Println("directory")
+	case len(opt.Values) > 0 || opt.Complete != nil:
+		fmt.Println("values")
+		suggestions, err := opt.Suggestions()
+		if err != nil {
+			return
+		}
+		for _, suggestion := range suggestions {
+			fmt.Println(suggestion)
+		}
+	default:
 		fmt.Println("file")
 	}
 }
 
+// findOption finds an option by its name or short name.
+func findOption(options []*option.Option, name string) *option.Option {
+	for _, opt := range options {
+		if opt.Name == name || (opt.Short != "" && opt.Short == name) {
+			return opt
+		}
+	}
+
+	return nil
+}
+
+// printCommand prints a command name and its usage, separated by a colon.
+// Colons in the name are escaped with a backslash, so that namespaced names
+// such as db:migrate can be told apart from the usage.
 func printCommand(command cli.Command) {
 	if command.Hidden {
 		return
 	}
 	fmt.Printf(
 		"%s:%s\n",
-		command.Name,
+		strings.Replace(command.Name, ":", `\:`, -1),
 		strings.Replace(command.Usage, "\n", "", -1),
 	)
 }
@@ -0,0 +1,96 @@
+package appcli
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// completionScripts are the completion scripts for each supported shell.
+var completionScripts = map[string]string{
+	"bash": bashCompletion,
+	"zsh":  zshCompletion,
+	"fish": fishCompletion,
+}
+
+// installCompletion adds completion for a shell to the user's shell config.
+// The path to the file is returned, along with whether it was changed.
+func installCompletion(shell string) (string, bool, error) {
+	home := os.Getenv("HOME")
+	if home == "" {
+		return "", false, errors.New("unable to find home directory: HOME is not set")
+	}
+
+	switch shell {
+	case "bash":
+		return appendLine(filepath.Join(home, ".bashrc"), sourceLine(shell))
+	case "zsh":
+		dir := os.Getenv("ZDOTDIR")
+		if dir == "" {
+			dir = home
+		}
+		return appendLine(filepath.Join(dir, ".zshrc"), sourceLine(shell))
+	case "fish":
+		dir := os.Getenv("XDG_CONFIG_HOME")
+		if dir == "" {
+			dir = filepath.Join(home, ".config")
+		}
+		path := filepath.Join(dir, "fish", "completions", "tusk.fish")
+		return writeFile(path, fishCompletion)
+	default:
+		return "", false, fmt.Errorf("unsupported shell: %s", shell)
+	}
+}
+
+// sourceLine returns the line that loads completion in a shell config file.
+func sourceLine(shell string) string {
+	return fmt.Sprintf(`eval "$(tusk completion %s)"`, shell)
+}
+
+// appendLine adds a line to the end of a file, unless it is already present.
+func appendLine(path string, line string) (string, bool, error) {
+	existing, err := ioutil.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return path, false, err
+	}
+
+	for _, l := range strings.Split(string(existing), "\n") {
+		if strings.TrimSpace(l) == line {
+			return path, false, nil
+		}
+	}
+
+	content := line + "\n"
+	if len(existing) > 0 && !strings.HasSuffix(string(existing), "\n") {
+		content = "\n" + content
+	}
+
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return path, false, err
+	}
+
+	if _, err := f.WriteString(content); err != nil {
+		f.Close() // nolint: errcheck, gas
+		return path, false, err
+	}
+
+	return path, true, f.Close()
+}
+
+// writeFile writes a file and its parent directories, unless the file
+// already has the same content.
+func writeFile(path string, content string) (string, bool, error) {
+	if existing, err := ioutil.ReadFile(path); err == nil && string(existing) == content {
+		return path, false, nil
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return path, false, err
+	}
+
+	return path, true, ioutil.WriteFile(path, []byte(content), 0644)
+}
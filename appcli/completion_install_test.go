@@ -0,0 +1,89 @@
+package appcli
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestCompletionScripts(t *testing.T) {
+	files := map[string]string{
+		"bash": "tusk-completion.bash",
+		"zsh":  "_tusk",
+		"fish": "tusk.fish",
+	}
+
+	for shell, file := range files {
+		expected, err := ioutil.ReadFile(filepath.Join("..", "completion", file))
+		if err != nil {
+			t.Fatalf("unexpected error reading %s: %s", file, err)
+		}
+
+		if completionScripts[shell] != string(expected) {
+			t.Errorf("completion script for %s does not match completion/%s", shell, file)
+		}
+	}
+}
+
+func TestInstallCompletion(t *testing.T) {
+	home, err := ioutil.TempDir("", "tusk-home")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	defer os.RemoveAll(home) // nolint: errcheck
+
+	for _, key := range []string{"HOME", "ZDOTDIR", "XDG_CONFIG_HOME"} {
+		defer os.Setenv(key, os.Getenv(key)) // nolint: errcheck
+		os.Unsetenv(key)                     // nolint: errcheck, gas
+	}
+	os.Setenv("HOME", home) // nolint: errcheck, gas
+
+	bashrc := filepath.Join(home, ".bashrc")
+	if err := ioutil.WriteFile(bashrc, []byte("export FOO=bar"), 0644); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	tests := []struct {
+		shell   string
+		path    string
+		changed bool
+	}{
+		{"bash", bashrc, true},
+		{"bash", bashrc, false},
+		{"zsh", filepath.Join(home, ".zshrc"), true},
+		{"fish", filepath.Join(home, ".config", "fish", "completions", "tusk.fish"), true},
+		{"fish", filepath.Join(home, ".config", "fish", "completions", "tusk.fish"), false},
+	}
+
+	for _, tt := range tests {
+		path, changed, err := installCompletion(tt.shell)
+		if err != nil {
+			t.Errorf("installCompletion(%s): unexpected error: %s", tt.shell, err)
+			continue
+		}
+
+		if path != tt.path || changed != tt.changed {
+			t.Errorf(
+				"installCompletion(%s): expected (%s, %t), actual (%s, %t)",
+				tt.shell, tt.path, tt.changed, path, changed,
+			)
+		}
+	}
+
+	content, err := ioutil.ReadFile(bashrc)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	expected := "export FOO=bar\n" + sourceLine("bash") + "\n"
+	if string(content) != expected {
+		t.Errorf("expected .bashrc:\n%s\nactual:\n%s", expected, content)
+	}
+
+	if _, _, err := installCompletion("powershell"); err == nil ||
+		!strings.Contains(err.Error(), "unsupported shell") {
+		t.Errorf("installCompletion(powershell): expected unsupported shell error, actual %v", err)
+	}
+}
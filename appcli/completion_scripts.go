@@ -0,0 +1,154 @@
+package appcli
+
+// The completion scripts are kept in sync with the files in the completion
+// directory, which are included in releases.
+
+// bashCompletion is the completion script for bash.
+const bashCompletion = `#!/bin/bash
+
+_tusk_bash_autocomplete() {
+    local cur line words opts meta
+    local -a args
+    COMPREPLY=()
+
+    # Task names may contain colons, which bash also splits words on, so the
+    # words are read from the command line instead
+    line="${COMP_LINE:0:$COMP_POINT}"
+    read -ra args <<< "${line}"
+    cur=""
+    if [[ "${line}" != *[[:space:]] ]]; then
+        cur="${args[${#args[@]}-1]}"
+        unset 'args[${#args[@]}-1]'
+    fi
+    words="$( "${args[@]}" --generate-bash-completion )"
+
+    # Split words into completion type and options
+    meta="$( echo "${words}" | head -n1 )"
+    opts="$( echo "${words}" | tail -n +2 )"
+
+    case "${meta}" in
+        normal)
+            declare -a values tasks flags
+            # Names end at the first colon not escaped with a backslash
+            values=( $( echo "${opts}" | sed -e 's/\([^\\]\):.*$/\1/' -e 's/\\:/:/g' ) )
+            for option in "${values[@]}"; do
+                if [[ "${option}" = --* ]]; then
+                    flags+=("${option}")
+                else
+                    tasks+=("${option}")
+                fi
+            done
+
+            if [[ "${cur}" = --* ]]; then
+                COMPREPLY=( $(compgen -W "${flags[*]}" -- "${cur}") )
+            else
+                COMPREPLY=( $(compgen -W "${tasks[*]}" -- "${cur}") )
+            fi
+            ;;
+        file)
+            COMPREPLY=( $(compgen -f -- "${cur}") )
+            ;;
+        directory)
+            COMPREPLY=( $(compgen -d -- "${cur}") )
+            ;;
+        values)
+            local IFS=$'\n'
+            COMPREPLY=( $(compgen -W "${opts}" -- "${cur}") )
+            ;;
+    esac
+
+    # Bash only replaces the text after the last colon of the current word
+    if [[ "${cur}" = *:* && "${COMP_WORDBREAKS}" = *:* ]]; then
+        local prefix="${cur%"${cur##*:}"}"
+        COMPREPLY=( "${COMPREPLY[@]#"${prefix}"}" )
+    fi
+
+    return 0
+}
+
+complete -o filenames -o bashdefault -F _tusk_bash_autocomplete tusk
+`
+
+// zshCompletion is the completion script for zsh.
+const zshCompletion = `#compdef tusk
+
+_tusk() {
+    local meta end
+    local -a _words _options
+
+    let end=$CURRENT-1
+    IFS=$'\n' _words=( $(${words[@]:0:$end} --generate-bash-completion) )
+
+    # Split words into completion type and options
+    meta="${_words[1]}"
+    _options=( "${_words[@]:1}" )
+
+    case "${meta}" in
+        "normal")
+            local -a _tasks _flags
+            for option in "${_options[@]}"; do
+                if [[ "${option}" = --* ]]; then
+                    _flags+=("${option}")
+                else
+                    _tasks+=("${option}")
+                fi
+            done
+            # Colons escaped with a backslash are kept as part of the name
+            _describe -t tasks 'tasks' _tasks
+            _describe -t flags 'flags' _flags
+            ;;
+        "file")
+            _files
+            ;;
+        "directory")
+            _files -/
+            ;;
+        "values")
+            compadd -- "${_options[@]}"
+            ;;
+    esac
+}
+
+# Support both autoloading from fpath and sourcing directly
+if [[ "${funcstack[1]}" = "_tusk" ]]; then
+    _tusk "$@"
+else
+    compdef _tusk tusk
+fi
+`
+
+// fishCompletion is the completion script for fish.
+const fishCompletion = `function __tusk_complete
+    set -l args (commandline -opc)
+    set -l current (commandline -ct)
+    set -l words ($args --generate-bash-completion 2>/dev/null)
+
+    # Split words into completion type and options
+    set -l meta $words[1]
+    set -e words[1]
+
+    switch "$meta"
+        case normal
+            for option in $words
+                # Names end at the first colon not escaped with a backslash
+                set -l parts (string match -r -- '^((?:[^\\\\:]|\\\\.)*):(.*)$' $option)
+                or continue
+                set -l name (string replace -a -- '\\:' ':' $parts[2])
+                if string match -q -- '--*' $name
+                    string match -q -- '-*' $current; or continue
+                else
+                    string match -q -- '-*' $current; and continue
+                end
+                printf '%s\t%s\n' $name "$parts[3]"
+            end
+        case file
+            __fish_complete_path $current
+        case directory
+            __fish_complete_directories $current
+        case values
+            printf '%s\n' $words
+    end
+end
+
+complete -c tusk -f -a '(__tusk_complete)'
+`
@@ -0,0 +1,101 @@
+package appcli
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+	"testing"
+
+	"github.com/urfave/cli"
+)
+
+// captureStdout returns everything printed to stdout by a function.
+func captureStdout(t *testing.T, f func()) string {
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatalf("os.Pipe(): unexpected err: %s", err)
+	}
+
+	stdout := os.Stdout
+	os.Stdout = w
+	defer func() { os.Stdout = stdout }()
+
+	f()
+	w.Close() // nolint: errcheck, gas
+
+	var buf bytes.Buffer
+	if _, err := io.Copy(&buf, r); err != nil {
+		t.Fatalf("io.Copy(): unexpected err: %s", err)
+	}
+
+	return buf.String()
+}
+
+var namespacedCommands = []cli.Command{
+	{Name: "db:migrate", Usage: "Migrate the database: all of it"},
+	{Name: "db:seed", Usage: "Seed the database"},
+	{Name: "test"},
+}
+
+func TestPrintCommand_namespaced(t *testing.T) {
+	actual := captureStdout(t, func() {
+		for _, command := range namespacedCommands {
+			printCommand(command)
+		}
+	})
+
+	expected := `db\:migrate:Migrate the database: all of it
+db\:seed:Seed the database
+test:
+`
+	if expected != actual {
+		t.Errorf("printCommand(): expected %q, actual %q", expected, actual)
+	}
+}
+
+var bashcompletiontests = []struct {
+	line     string
+	expected []string
+}{
+	{"tusk d", []string{"db:migrate", "db:seed"}},
+	{"tusk db:m", []string{"migrate"}},
+	{"tusk db:", []string{"migrate", "seed"}},
+	{"tusk t", []string{"test"}},
+}
+
+func TestBashCompletion_namespaced(t *testing.T) {
+	if _, err := exec.LookPath("bash"); err != nil {
+		t.Skip("bash is not installed")
+	}
+
+	output := "normal\n" + captureStdout(t, func() {
+		for _, command := range namespacedCommands {
+			printCommand(command)
+		}
+	})
+
+	for _, tt := range bashcompletiontests {
+		script := bashCompletion + `
+tusk() { printf '%s' "$TUSK_OUTPUT"; }
+COMP_LINE="$TUSK_LINE"
+COMP_POINT=${#COMP_LINE}
+COMP_WORDBREAKS=$' \t\n"\'><=;|&(:'
+_tusk_bash_autocomplete
+printf '%s\n' "${COMPREPLY[@]}"
+`
+		cmd := exec.Command("bash", "-c", script)
+		cmd.Env = append(os.Environ(), "TUSK_OUTPUT="+output, "TUSK_LINE="+tt.line)
+		out, err := cmd.Output()
+		if err != nil {
+			t.Errorf("completion for %q: unexpected err: %s", tt.line, err)
+			continue
+		}
+
+		actual := strings.Fields(string(out))
+		if strings.Join(tt.expected, " ") != strings.Join(actual, " ") {
+			t.Errorf("completion for %q: expected %v, actual %v", tt.line, tt.expected, actual)
+		}
+	}
+}
@@ -0,0 +1,88 @@
+package appcli
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/urfave/cli"
+
+	"github.com/rliebz/tusk/config"
+	"github.com/rliebz/tusk/config/option"
+	"github.com/rliebz/tusk/ui"
+)
+
+const explainCommandName = "explain"
+
+// addExplainCommand adds a command that explains where the value of each
+// option used by a task comes from. The command has a copy of every task
+// command as a sub-command, so that the same flags can be passed. If
+// explain is set, the sub-commands print the explanation instead of keeping
+// the action of the task command. Otherwise, the sub-commands also record in
+// the app metadata that options are being explained, so that options which
+// cannot get a value do not stop the explanation.
+func addExplainCommand(app *cli.App, cfg *config.Config, explain bool) error {
+	if _, ok := cfg.FindTask(explainCommandName); ok {
+		return nil
+	}
+
+	var subcommands []cli.Command
+	for _, command := range app.Commands {
+		if explain {
+			t, ok := cfg.FindTask(command.Name)
+			if !ok {
+				continue
+			}
+
+			options, err := cfg.FindAllOptions(t)
+			if err != nil {
+				return err
+			}
+
+			command.Action = createExplainAction(options)
+		} else if action, ok := command.Action.(func(*cli.Context) error); ok {
+			command.Action = func(c *cli.Context) error {
+				app.Metadata["explain"] = true
+				return action(c)
+			}
+		}
+
+		subcommands = append(subcommands, command)
+	}
+
+	addBuiltinCommand(app, cli.Command{
+		Name:        explainCommandName,
+		Usage:       "Explain where the option values of a task come from",
+		ArgsUsage:   "<task>",
+		Subcommands: subcommands,
+	})
+
+	return nil
+}
+
+// createExplainAction prints how each option passed got its value.
+func createExplainAction(options []*option.Option) func(c *cli.Context) error {
+	return func(c *cli.Context) error {
+		if c.Args().Present() {
+			return fmt.Errorf("unexpected argument: %s", c.Args().First())
+		}
+
+		sorted := make([]*option.Option, len(options))
+		copy(sorted, options)
+		sort.Slice(sorted, func(i, j int) bool {
+			return sorted[i].Name < sorted[j].Name
+		})
+
+		if len(sorted) == 0 {
+			ui.Info("Task uses no options")
+			return nil
+		}
+
+		for _, opt := range sorted {
+			for _, line := range opt.Explain() {
+				ui.Println(line)
+			}
+		}
+
+		return nil
+	}
+}
@@ -0,0 +1,109 @@
+package appcli
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/rliebz/tusk/config"
+	"github.com/urfave/cli"
+)
+
+func TestNewFlagApp_explain(t *testing.T) {
+	cfgText := []byte(`options:
+  foo:
+    default: foovalue
+
+tasks:
+  mytask:
+    run: echo ${foo}
+`)
+
+	flagApp, err := newFlagApp(&config.Metadata{CfgText: cfgText})
+	if err != nil {
+		t.Fatalf("newFlagApp(): unexpected err: %s", err)
+	}
+
+	args := []string{"tusk", "explain", "mytask", "--foo", "other"}
+	if err = flagApp.Run(args); err != nil {
+		t.Fatalf("flagApp.Run(): unexpected err: %s", err)
+	}
+
+	command, ok := flagApp.Metadata["command"].(*cli.Command)
+	if !ok {
+		t.Fatalf(
+			"flagApp.Metadata: command not a *cli.Command: %#v",
+			flagApp.Metadata["command"],
+		)
+	}
+
+	if command.Name != "mytask" {
+		t.Errorf(`flagApp.Metadata["command"]: expected mytask, actual %s`, command.Name)
+	}
+
+	if explain, _ := flagApp.Metadata["explain"].(bool); !explain {
+		t.Error(`flagApp.Metadata["explain"]: expected true, actual false`)
+	}
+
+	expected := map[string]string{"foo": "other"}
+	if actual := flagApp.Metadata["flagsPassed"]; !reflect.DeepEqual(expected, actual) {
+		t.Errorf(`flagApp.Metadata["flagsPassed"]: expected %#v, actual %#v`, expected, actual)
+	}
+}
+
+func TestAddExplainCommand(t *testing.T) {
+	cfg, err := config.Parse([]byte(`tasks: {foo: {}, bar: {}}`))
+	if err != nil {
+		t.Fatalf("config.Parse(): unexpected err: %s", err)
+	}
+
+	app := newBaseApp()
+	if err = addTasks(app, cfg, createExecuteCommand); err != nil {
+		t.Fatalf("addTasks(): unexpected err: %s", err)
+	}
+
+	if err = addExplainCommand(app, cfg, true); err != nil {
+		t.Fatalf("addExplainCommand(): unexpected err: %s", err)
+	}
+
+	command := app.Command("explain")
+	if command == nil {
+		t.Fatal(`addExplainCommand(): expected command "explain", got none`)
+	}
+
+	if !command.Hidden {
+		t.Error(`addExplainCommand(): expected command "explain" to be hidden from the tasks`)
+	}
+
+	if builtins := builtinsOf(app); len(builtins) != 1 || builtins[0].Name != "explain" {
+		t.Errorf(`addExplainCommand(): expected only built-in command "explain", actual %v`, builtins)
+	}
+
+	var names []string
+	for _, subcommand := range command.Subcommands {
+		names = append(names, subcommand.Name)
+	}
+
+	if expected := []string{"bar", "foo"}; !reflect.DeepEqual(expected, names) {
+		t.Errorf("addExplainCommand(): expected sub-commands %v, actual %v", expected, names)
+	}
+}
+
+func TestAddExplainCommand_overridden(t *testing.T) {
+	cfg, err := config.Parse([]byte(`tasks: {explain: {}}`))
+	if err != nil {
+		t.Fatalf("config.Parse(): unexpected err: %s", err)
+	}
+
+	app := newBaseApp()
+	if err = addTasks(app, cfg, createExecuteCommand); err != nil {
+		t.Fatalf("addTasks(): unexpected err: %s", err)
+	}
+
+	if err = addExplainCommand(app, cfg, true); err != nil {
+		t.Fatalf("addExplainCommand(): unexpected err: %s", err)
+	}
+
+	if len(app.Commands) != 1 {
+		t.Errorf("addExplainCommand(): expected only the task command, actual %d commands", len(app.Commands))
+	}
+}
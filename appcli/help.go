@@ -26,7 +26,10 @@ Description:
 
 Tasks:{{range .VisibleCategories}}{{$categoryName := .Name}}{{if $categoryName}}
    {{$categoryName}}:{{end}}{{range .VisibleCommands}}
-   {{if $categoryName}}  {{end}}{{join .Names ", "}}{{"\t"}}{{.Usage}}{{end}}{{end}}{{end}}{{if .VisibleFlags}}
+   {{if $categoryName}}  {{end}}{{join .Names ", "}}{{"\t"}}{{.Usage}}{{end}}{{end}}{{end}}{{with builtins .}}
+
+Commands:{{range .}}
+   {{join .Names ", "}}{{"\t"}}{{.Usage}}{{end}}{{end}}{{if .VisibleFlags}}
 
 Global Options:
    {{range $index, $option := .VisibleFlags}}{{if $index}}
@@ -39,7 +42,10 @@ Copyright:
 	cli.CommandHelpTemplate = `{{.HelpName}}{{if .Usage}} - {{.Usage}}{{end}}
 
 Usage:
-   {{if .UsageText}}{{.UsageText}}{{else}}{{.HelpName}}{{if .VisibleFlags}} [options]{{end}} {{if .ArgsUsage}}{{.ArgsUsage}}{{end}}{{end}}{{if .Category}}
+   {{if .UsageText}}{{.UsageText}}{{else}}{{.HelpName}}{{if .VisibleFlags}} [options]{{end}} {{if .ArgsUsage}}{{.ArgsUsage}}{{end}}{{end}}{{if .Aliases}}
+
+Aliases:
+   {{join .Aliases ", "}}{{end}}{{if .Category}}
 
 Category:
    {{.Category}}{{end}}{{if .Description}}
@@ -59,9 +65,10 @@ func ShowAppHelp(app *cli.App) {
 	cli.HelpPrinter(app.Writer, cli.AppHelpTemplate, app)
 }
 
-// helpPrinter includes the custom indent template function.
+// helpPrinter includes the custom indent and builtins template functions.
 func helpPrinter(out io.Writer, templ string, data interface{}) {
 	customFunc := map[string]interface{}{
+		"builtins": builtinsOf,
 		"indent": func(spaces int, text string) string {
 			padding := strings.Repeat(" ", spaces)
 			return padding + strings.Replace(text, "\n", "\n"+padding, -1)
@@ -0,0 +1,167 @@
+package appcli
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"os/signal"
+	"path/filepath"
+	"syscall"
+	"time"
+
+	"github.com/rliebz/tusk/config/run"
+	"github.com/rliebz/tusk/config/task"
+	"github.com/rliebz/tusk/ui"
+	"github.com/rliebz/tusk/watch"
+)
+
+// watchTask runs a task, then runs it again each time the files it watches
+// change. If the task is still running when files change, its commands are
+// killed before it is run again. Options are not evaluated again, so every
+// run uses the same option values.
+func watchTask(t *task.Task) error {
+	patterns, fallback, err := watchPatterns(t)
+	if err != nil {
+		return err
+	}
+
+	w, err := watch.New(patterns)
+	if err != nil {
+		return err
+	}
+	defer w.Close() // nolint: errcheck
+
+	// Commands are in their own process groups so that they can be killed
+	// along with anything they start, which means they no longer receive
+	// interrupts from the terminal, so interrupts are passed along here
+	run.ProcessGroups = true
+	interrupts := make(chan os.Signal, 1)
+	signal.Notify(interrupts, os.Interrupt, syscall.SIGTERM)
+	defer signal.Stop(interrupts)
+
+	return watchRuns(t, w.Changes(), interrupts, fallback)
+}
+
+// watchRuns runs a task each time files change until interrupted.
+//
+// If the whole directory of the task is watched, the files the task writes
+// are watched as well. Changes do not stop the task in that case, and files
+// written while it runs are ignored, so that it does not keep running itself.
+func watchRuns(
+	t *task.Task, changes <-chan []string, interrupts <-chan os.Signal, fallback bool,
+) error {
+	started := time.Now()
+	for {
+		done := make(chan error, 1)
+		go func() { done <- executeTask(t) }()
+
+		var changed, pending []string
+		restart := false
+	running:
+		for {
+			select {
+			case err := <-done:
+				// Failed commands are already printed along with their exit status
+				if _, ok := err.(*exec.ExitError); err != nil && !ok {
+					ui.Error(err)
+				}
+				break running
+			case paths := <-changes:
+				if fallback {
+					pending = append(pending, paths...)
+					continue
+				}
+				run.KillAll()
+				<-done
+				run.Resume()
+				changed, restart = paths, true
+				break running
+			case <-interrupts:
+				run.KillAll()
+				<-done
+				return nil
+			}
+		}
+
+		if !restart {
+			ui.Info("Waiting for changes")
+			finished := time.Now()
+
+			changed = writtenOutside(pending, started, finished)
+			restart = len(changed) > 0
+			for !restart {
+				select {
+				case changed = <-changes:
+					// An empty list means that changes may have been missed
+					if fallback && len(changed) > 0 {
+						changed = writtenOutside(changed, started, finished)
+						restart = len(changed) > 0
+					} else {
+						restart = true
+					}
+				case <-interrupts:
+					return nil
+				}
+			}
+		}
+
+		started = time.Now()
+		ui.ClearSummary()
+		ui.Info(fmt.Sprintf("%s changed, running %s again", describeChanges(changed), t.Name))
+	}
+}
+
+// writtenOutside returns the paths that were not last modified between the
+// start and the end of a run. Paths that no longer exist are included.
+func writtenOutside(paths []string, started, finished time.Time) []string {
+	// File systems may only store modification times to the second
+	started = started.Truncate(time.Second)
+
+	var outside []string
+	for _, path := range paths {
+		info, err := os.Stat(path)
+		if err == nil && !info.ModTime().Before(started) && !info.ModTime().After(finished) {
+			continue
+		}
+
+		outside = append(outside, path)
+	}
+
+	return outside
+}
+
+// watchPatterns returns the patterns of the files to watch for a task. If
+// neither the task nor its sub-tasks set any, every file in the directory of
+// the task is watched, which is reported as a fallback.
+func watchPatterns(t *task.Task) (patterns []string, fallback bool, err error) {
+	patterns, err = t.WatchPatterns()
+	if err != nil || len(patterns) > 0 {
+		return patterns, false, err
+	}
+
+	dir, err := filepath.Abs(t.Dir)
+	if err != nil {
+		return nil, false, err
+	}
+
+	return []string{filepath.Join(dir, "**")}, true, nil
+}
+
+// describeChanges describes the files that changed for a message.
+func describeChanges(paths []string) string {
+	switch len(paths) {
+	case 0:
+		return "Files"
+	case 1:
+		wd, err := os.Getwd()
+		if err != nil {
+			return paths[0]
+		}
+		if rel, err := filepath.Rel(wd, paths[0]); err == nil {
+			return rel
+		}
+		return paths[0]
+	default:
+		return fmt.Sprintf("%d files", len(paths))
+	}
+}
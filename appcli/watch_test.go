@@ -0,0 +1,117 @@
+package appcli
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/rliebz/tusk/config/marshal"
+	"github.com/rliebz/tusk/config/run"
+	"github.com/rliebz/tusk/config/task"
+	"github.com/rliebz/tusk/ui"
+	"github.com/rliebz/tusk/watch"
+)
+
+func TestWatchPatterns(t *testing.T) {
+	dir := filepath.Join(os.TempDir(), "project")
+
+	actual, fallback, err := watchPatterns(&task.Task{Dir: dir, Watch: marshal.StringList{"*.go"}})
+	if err != nil {
+		t.Fatalf("watchPatterns(): unexpected err: %s", err)
+	}
+
+	if fallback {
+		t.Error("watchPatterns(): expected no fallback for a task with patterns")
+	}
+
+	expected := []string{filepath.Join(dir, "*.go")}
+	if !reflect.DeepEqual(expected, actual) {
+		t.Errorf("watchPatterns(): expected %v, actual %v", expected, actual)
+	}
+}
+
+func TestWatchPatterns_default(t *testing.T) {
+	dir := filepath.Join(os.TempDir(), "project")
+
+	actual, fallback, err := watchPatterns(&task.Task{Dir: dir})
+	if err != nil {
+		t.Fatalf("watchPatterns(): unexpected err: %s", err)
+	}
+
+	if !fallback {
+		t.Error("watchPatterns(): expected a fallback for a task without patterns")
+	}
+
+	expected := []string{filepath.Join(dir, "**")}
+	if !reflect.DeepEqual(expected, actual) {
+		t.Errorf("watchPatterns(): expected %v, actual %v", expected, actual)
+	}
+}
+
+func TestWatchRuns_writes_to_task_directory(t *testing.T) {
+	defer func(verbosity ui.VerbosityLevel) { ui.Verbosity = verbosity }(ui.Verbosity)
+	ui.Verbosity = ui.VerbosityLevelSilent
+
+	dir, err := ioutil.TempDir("", "tusk-watch")
+	if err != nil {
+		t.Fatalf("ioutil.TempDir(): unexpected err: %s", err)
+	}
+	defer os.RemoveAll(dir) // nolint: errcheck
+
+	patterns, fallback, err := watchPatterns(&task.Task{Dir: dir})
+	if err != nil {
+		t.Fatalf("watchPatterns(): unexpected err: %s", err)
+	}
+
+	w, err := watch.New(patterns)
+	if err != nil {
+		t.Fatalf("watch.New(): unexpected err: %s", err)
+	}
+	defer w.Close() // nolint: errcheck
+
+	tsk := &task.Task{
+		Name: "write",
+		Dir:  dir,
+		Run:  run.List{{Command: marshal.StringList{"echo run >> runs.txt"}}},
+	}
+
+	interrupts := make(chan os.Signal, 1)
+	errs := make(chan error, 1)
+	go func() { errs <- watchRuns(tsk, w.Changes(), interrupts, fallback) }()
+
+	countRuns := func() int {
+		data, _ := ioutil.ReadFile(filepath.Join(dir, "runs.txt")) // nolint: errcheck
+		return strings.Count(string(data), "run\n")
+	}
+
+	time.Sleep(2 * time.Second)
+	if runs := countRuns(); runs != 1 {
+		t.Errorf("watchRuns(): expected 1 run before any change, actual %d", runs)
+	}
+
+	source := filepath.Join(dir, "source.txt")
+	if err = ioutil.WriteFile(source, []byte("changed"), 0644); err != nil {
+		t.Fatalf("ioutil.WriteFile(): unexpected err: %s", err)
+	}
+
+	for deadline := time.Now().Add(5 * time.Second); countRuns() < 2; {
+		if time.Now().After(deadline) {
+			break
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+
+	time.Sleep(2 * time.Second)
+	if runs := countRuns(); runs != 2 {
+		t.Errorf("watchRuns(): expected 2 runs after one change, actual %d", runs)
+	}
+
+	interrupts <- os.Interrupt
+	if err := <-errs; err != nil {
+		t.Errorf("watchRuns(): unexpected err: %s", err)
+	}
+}
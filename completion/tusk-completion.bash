@@ -1,10 +1,20 @@
 #!/bin/bash
 
 _tusk_bash_autocomplete() {
-    local cur words opts meta
+    local cur line words opts meta
+    local -a args
     COMPREPLY=()
-    cur="${COMP_WORDS[COMP_CWORD]}"
-    words="$( "${COMP_WORDS[@]:0:$COMP_CWORD}" --generate-bash-completion | cut -f1 -d":")"
+
+    # Task names may contain colons, which bash also splits words on, so the
+    # words are read from the command line instead
+    line="${COMP_LINE:0:$COMP_POINT}"
+    read -ra args <<< "${line}"
+    cur=""
+    if [[ "${line}" != *[[:space:]] ]]; then
+        cur="${args[${#args[@]}-1]}"
+        unset 'args[${#args[@]}-1]'
+    fi
+    words="$( "${args[@]}" --generate-bash-completion )"
 
     # Split words into completion type and options
     meta="$( echo "${words}" | head -n1 )"
@@ -13,7 +23,8 @@ _tusk_bash_autocomplete() {
     case "${meta}" in
         normal)
             declare -a values tasks flags
-            values=( ${opts} )
+            # Names end at the first colon not escaped with a backslash
+            values=( $( echo "${opts}" | sed -e 's/\([^\\]\):.*$/\1/' -e 's/\\:/:/g' ) )
             for option in "${values[@]}"; do
                 if [[ "${option}" = --* ]]; then
                     flags+=("${option}")
@@ -31,8 +42,21 @@ _tusk_bash_autocomplete() {
         file)
             COMPREPLY=( $(compgen -f -- "${cur}") )
             ;;
+        directory)
+            COMPREPLY=( $(compgen -d -- "${cur}") )
+            ;;
+        values)
+            local IFS=$'\n'
+            COMPREPLY=( $(compgen -W "${opts}" -- "${cur}") )
+            ;;
     esac
 
+    # Bash only replaces the text after the last colon of the current word
+    if [[ "${cur}" = *:* && "${COMP_WORDBREAKS}" = *:* ]]; then
+        local prefix="${cur%"${cur##*:}"}"
+        COMPREPLY=( "${COMPREPLY[@]#"${prefix}"}" )
+    fi
+
     return 0
 }
 
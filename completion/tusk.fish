@@ -0,0 +1,33 @@
+function __tusk_complete
+    set -l args (commandline -opc)
+    set -l current (commandline -ct)
+    set -l words ($args --generate-bash-completion 2>/dev/null)
+
+    # Split words into completion type and options
+    set -l meta $words[1]
+    set -e words[1]
+
+    switch "$meta"
+        case normal
+            for option in $words
+                # Names end at the first colon not escaped with a backslash
+                set -l parts (string match -r -- '^((?:[^\\\\:]|\\\\.)*):(.*)$' $option)
+                or continue
+                set -l name (string replace -a -- '\\:' ':' $parts[2])
+                if string match -q -- '--*' $name
+                    string match -q -- '-*' $current; or continue
+                else
+                    string match -q -- '-*' $current; and continue
+                end
+                printf '%s\t%s\n' $name "$parts[3]"
+            end
+        case file
+            __fish_complete_path $current
+        case directory
+            __fish_complete_directories $current
+        case values
+            printf '%s\n' $words
+    end
+end
+
+complete -c tusk -f -a '(__tusk_complete)'
@@ -1,7 +1,13 @@
 package config
 
 import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
 	"github.com/rliebz/tusk/config/option"
+	"github.com/rliebz/tusk/config/shell"
 	"github.com/rliebz/tusk/config/task"
 	"github.com/rliebz/tusk/ui"
 	yaml "gopkg.in/yaml.v2"
@@ -11,6 +17,19 @@ import (
 type Config struct {
 	Options map[string]*option.Option
 	Tasks   map[string]*task.Task
+
+	// Default is the task to run when no task is passed.
+	Default string `yaml:",omitempty"`
+
+	// Interpreter runs every command for tasks without their own interpreter.
+	Interpreter shell.Interpreter `yaml:",omitempty"`
+
+	// Inherit merges in the config files found in parent directories.
+	Inherit bool `yaml:",omitempty"`
+
+	// optionOrder is the name of every option in the order it is defined,
+	// with global options first.
+	optionOrder []string
 }
 
 // New is the constructor for Config.
@@ -21,17 +40,82 @@ func New() *Config {
 	}
 }
 
-// Parse loads the contents of a config file into a struct.
+// Parse loads the contents of a config file into a struct. Errors are
+// returned as an *Error with the location of the problem when possible.
 func Parse(text []byte) (*Config, error) {
+	cfg, err := parse(text)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := checkDefault(text, cfg); err != nil {
+		return nil, err
+	}
+
+	return cfg, nil
+}
+
+// parse loads the contents of a single config file, which may refer to tasks
+// defined by other config files.
+func parse(text []byte) (*Config, error) {
 	cfg := New()
 
 	if err := yaml.UnmarshalStrict(text, &cfg); err != nil {
+		return nil, locateError(text, err)
+	}
+
+	if err := checkAliases(text, cfg); err != nil {
 		return nil, err
 	}
 
 	return cfg, nil
 }
 
+// FindTask returns the task with the given name or alias.
+func (c *Config) FindTask(name string) (*task.Task, bool) {
+	if t, ok := c.Tasks[name]; ok {
+		return t, true
+	}
+
+	for _, t := range c.Tasks {
+		if t.HasName(name) {
+			return t, true
+		}
+	}
+
+	return nil, false
+}
+
+// checkAliases ensures that every task name and alias refers to one task.
+func checkAliases(text []byte, cfg *Config) error {
+	var names []string
+	for name := range cfg.Tasks {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		for i, alias := range cfg.Tasks[name].Aliases {
+			for _, other := range names {
+				if other == name || !cfg.Tasks[other].HasName(alias) {
+					continue
+				}
+
+				path := []string{"tasks", name, "aliases", strconv.Itoa(i)}
+				line, column := Locate(text, path...)
+				return &Error{
+					Line:   line,
+					Column: column,
+					Path:   strings.Join(path, "."),
+					Err:    fmt.Errorf(`alias "%s" is already used by task "%s"`, alias, other),
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
 // UnmarshalYAML unmarshals and assigns names to options and tasks.
 func (c *Config) UnmarshalYAML(unmarshal func(interface{}) error) error {
 
@@ -42,10 +126,96 @@ func (c *Config) UnmarshalYAML(unmarshal func(interface{}) error) error {
 
 	for name, opt := range c.Options {
 		opt.Name = name
+		opt.Interpreter = c.Interpreter
 	}
 
 	for name, t := range c.Tasks {
 		t.Name = name
+		if len(t.Interpreter) == 0 {
+			t.Interpreter = c.Interpreter
+		}
+
+		for _, opt := range t.Options {
+			opt.Interpreter = t.Interpreter
+		}
+	}
+
+	var ordered yaml.MapSlice
+	if err := unmarshal(&ordered); err != nil {
+		return err
+	}
+
+	order, err := getOrderedOpts(ordered)
+	if err != nil {
+		return err
+	}
+	c.optionOrder = order
+
+	return nil
+}
+
+// checkDefault ensures that the default task exists and can be run directly.
+func checkDefault(text []byte, cfg *Config) error {
+	if cfg.Default == "" {
+		return nil
+	}
+
+	t, ok := cfg.FindTask(cfg.Default)
+	if ok && !t.Private {
+		return nil
+	}
+
+	err := fmt.Errorf(`default task "%s" is not defined`, cfg.Default)
+	if ok {
+		err = fmt.Errorf(`default task "%s" is private`, cfg.Default)
+	}
+
+	line, column := Locate(text, "default")
+	return &Error{
+		Line:   line,
+		Column: column,
+		Path:   "default",
+		Err:    err,
+	}
+}
+
+// getOrderedOpts returns a list of options in the order they appear.
+func getOrderedOpts(ordered yaml.MapSlice) ([]string, error) {
+
+	var allOpts yaml.MapSlice
+	for _, item := range ordered {
+		switch item.Key {
+		case "options":
+			opts, _ := item.Value.(yaml.MapSlice)
+			allOpts = append(allOpts, opts...)
+		case "tasks":
+			tasks, _ := item.Value.(yaml.MapSlice)
+			for _, t := range tasks {
+				allOpts = append(allOpts, getTaskOpts(t.Value)...)
+			}
+		}
+	}
+
+	var output []string
+	for _, item := range allOpts {
+		name, ok := item.Key.(string)
+		if !ok {
+			return nil, fmt.Errorf("failed to assert name as string: %v", item.Key)
+		}
+
+		output = append(output, name)
+	}
+
+	return output, nil
+}
+
+func getTaskOpts(t interface{}) yaml.MapSlice {
+	fields, _ := t.(yaml.MapSlice)
+	for _, field := range fields {
+		if field.Key == "options" {
+			opts, _ := field.Value.(yaml.MapSlice)
+			return opts
+		}
 	}
 
 	return nil
@@ -54,8 +224,17 @@ func (c *Config) UnmarshalYAML(unmarshal func(interface{}) error) error {
 // Metadata contains global configuration settings.
 type Metadata struct {
 	CfgText      []byte
+	CfgPath      string
 	Directory    string
+	Parents      []File
+	LocalPath    string
+	LocalValues  map[string]string
 	PrintHelp    bool
 	PrintVersion bool
+	PrefixOutput bool
+	Strict       bool
+	Watch        bool
+	LogFormat    ui.LogFormat
+	EventsFile   string
 	Verbosity    ui.VerbosityLevel
 }
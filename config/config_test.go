@@ -0,0 +1,114 @@
+package config
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/rliebz/tusk/config/shell"
+)
+
+func TestParse_default(t *testing.T) {
+	cfg, err := Parse([]byte(`
+default: build
+tasks:
+  build: {}
+`))
+	if err != nil {
+		t.Fatalf("Parse(): unexpected error: %s", err)
+	}
+
+	if expected := "build"; expected != cfg.Default {
+		t.Errorf("Parse(): expected Default: %s, actual: %s", expected, cfg.Default)
+	}
+}
+
+func TestParse_default_private(t *testing.T) {
+	_, err := Parse([]byte(`
+default: secret
+tasks:
+  secret: {private: true}
+`))
+	if err == nil {
+		t.Fatal("Parse(): expected error for private default task, actual nil")
+	}
+
+	expected := `line 2, column 1: default: default task "secret" is private`
+	if err.Error() != expected {
+		t.Errorf("Parse(): expected error %q, actual %q", expected, err.Error())
+	}
+}
+
+var findtasktests = []struct {
+	name     string
+	expected string
+	found    bool
+}{
+	{"build", "build", true},
+	{"b", "build", true},
+	{"test", "test", true},
+	{"missing", "", false},
+	{"", "", false},
+}
+
+func TestConfig_FindTask(t *testing.T) {
+	cfg, err := Parse([]byte(`
+tasks:
+  build: {aliases: [b]}
+  test: {}
+`))
+	if err != nil {
+		t.Fatalf("Parse(): unexpected error: %s", err)
+	}
+
+	for _, tt := range findtasktests {
+		found, ok := cfg.FindTask(tt.name)
+		if tt.found != ok {
+			t.Errorf("FindTask(%q): expected found: %t, actual: %t", tt.name, tt.found, ok)
+			continue
+		}
+
+		if ok && tt.expected != found.Name {
+			t.Errorf("FindTask(%q): expected %s, actual %s", tt.name, tt.expected, found.Name)
+		}
+	}
+}
+
+func TestParse_interpreter(t *testing.T) {
+	cfg, err := Parse([]byte(`
+interpreter: bash -eu -c
+options:
+  global: {}
+tasks:
+  inherited:
+    options:
+      foo: {}
+  custom:
+    interpreter: [zsh, -c]
+    options:
+      bar: {}
+`))
+	if err != nil {
+		t.Fatalf("Parse(): unexpected error: %s", err)
+	}
+
+	global := shell.Interpreter{"bash", "-eu", "-c"}
+	custom := shell.Interpreter{"zsh", "-c"}
+
+	interpretertests := []struct {
+		desc     string
+		actual   shell.Interpreter
+		expected shell.Interpreter
+	}{
+		{"global option", cfg.Options["global"].Interpreter, global},
+		{"inherited task", cfg.Tasks["inherited"].Interpreter, global},
+		{"inherited task option", cfg.Tasks["inherited"].Options["foo"].Interpreter, global},
+		{"custom task", cfg.Tasks["custom"].Interpreter, custom},
+		{"custom task option", cfg.Tasks["custom"].Options["bar"].Interpreter, custom},
+	}
+
+	for _, tt := range interpretertests {
+		if !reflect.DeepEqual(tt.expected, tt.actual) {
+			t.Errorf("Parse(): interpreter for %s: expected %v, actual %v", tt.desc, tt.expected, tt.actual)
+		}
+	}
+}
@@ -12,6 +12,9 @@ import (
 // AddSubTasks will recursively add task objects to the task's list of pretasks.
 func AddSubTasks(cfg *Config, t *task.Task) error {
 
+	// Sub-tasks are rebuilt on every call so that repeated calls are harmless
+	t.SubTasks = nil
+
 	for _, run := range t.Run {
 		for _, subTaskName := range run.Task {
 			// TODO: This requires tasks to be defined in order
@@ -37,14 +40,10 @@ func (cfg *Config) FindAllOptions(t *task.Task) ([]*option.Option, error) {
 		return nil, err
 	}
 
-	candidates := make(map[string]*option.Option)
-	for name, opt := range cfg.Options {
-		candidates[name] = opt
-	}
+	candidates := cfg.optionCandidates(t)
 
 	var required []*option.Option
-	for name, opt := range t.Options {
-		candidates[name] = opt
+	for _, opt := range t.Options {
 		required = append(required, opt)
 	}
 
@@ -68,6 +67,21 @@ func (cfg *Config) FindAllOptions(t *task.Task) ([]*option.Option, error) {
 	return required, nil
 }
 
+// optionCandidates returns the options that a task can refer to by name.
+// Options defined by the task take precedence over global options.
+func (cfg *Config) optionCandidates(t *task.Task) map[string]*option.Option {
+	candidates := make(map[string]*option.Option)
+	for name, opt := range cfg.Options {
+		candidates[name] = opt
+	}
+
+	for name, opt := range t.Options {
+		candidates[name] = opt
+	}
+
+	return candidates
+}
+
 func recurseDependencies(
 	entry []string, candidates map[string]*option.Option, found []*option.Option,
 ) ([]*option.Option, error) {
@@ -117,14 +131,7 @@ func getDependencies(item dependencyGetter) ([]string, error) {
 		return nil, err
 	}
 
-	re := interp.CompileGeneric()
-	groups := re.FindAllStringSubmatch(string(marshalled), -1)
-
-	var names []string
-	for _, group := range groups {
-		names = append(names, group[1])
-	}
-
+	names := interp.Names(string(marshalled))
 	names = append(names, item.Dependencies()...)
 
 	return names, nil
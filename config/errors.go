@@ -0,0 +1,205 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/rliebz/tusk/config/option"
+	"github.com/rliebz/tusk/config/run"
+	"github.com/rliebz/tusk/config/task"
+	yaml "gopkg.in/yaml.v2"
+)
+
+// linePattern matches the line number yaml includes in some error messages.
+var linePattern = regexp.MustCompile(`(?s)^(?:yaml: )?line (\d+): (.*)$`)
+
+// Error is an error found at a specific location in a config file.
+type Error struct {
+	// File is the path to the config file, if known.
+	File string
+	// Line and Column are the position in the config file, or 0 if unknown.
+	Line   int
+	Column int
+	// Path is the location of the error, such as tasks.test.options.verbose.
+	Path string
+	Err  error
+}
+
+// Error returns the error message prefixed with its location.
+func (e *Error) Error() string {
+	var location []string
+	switch {
+	case e.File != "" && e.Line != 0 && e.Column != 0:
+		location = append(location, fmt.Sprintf("%s:%d:%d", e.File, e.Line, e.Column))
+	case e.File != "" && e.Line != 0:
+		location = append(location, fmt.Sprintf("%s:%d", e.File, e.Line))
+	case e.File != "":
+		location = append(location, e.File)
+	case e.Line != 0 && e.Column != 0:
+		location = append(location, fmt.Sprintf("line %d, column %d", e.Line, e.Column))
+	case e.Line != 0:
+		location = append(location, fmt.Sprintf("line %d", e.Line))
+	}
+
+	if e.Path != "" {
+		location = append(location, e.Path)
+	}
+
+	return strings.Join(append(location, e.Err.Error()), ": ")
+}
+
+// Cause returns the underlying error.
+func (e *Error) Cause() error {
+	return e.Err
+}
+
+// WithFile adds the config file path to an error from parsing a config file.
+// Other errors, or errors that already have a path, are returned unchanged.
+func WithFile(err error, path string) error {
+	cfgErr, ok := err.(*Error)
+	if !ok || cfgErr.File != "" {
+		return err
+	}
+
+	withFile := *cfgErr
+	withFile.File = path
+	return &withFile
+}
+
+// locateError finds where in the config file an error from parsing occurred.
+func locateError(text []byte, err error) error {
+	message := err.Error()
+	if typeErr, ok := err.(*yaml.TypeError); ok && len(typeErr.Errors) > 0 {
+		message = strings.Join(typeErr.Errors, "\n  ")
+	}
+
+	if groups := linePattern.FindStringSubmatch(message); groups != nil {
+		line, _ := strconv.Atoi(groups[1])
+		path := PathAt(text, line)
+
+		cfgErr := &Error{Line: line, Path: strings.Join(path, "."), Err: errors.New(groups[2])}
+		if l, column := Locate(text, path...); l == line {
+			cfgErr.Column = column
+		}
+
+		return cfgErr
+	}
+
+	path := findInvalidPath(text)
+	if path == nil {
+		return err
+	}
+
+	line, column := Locate(text, path...)
+	return &Error{Line: line, Column: column, Path: strings.Join(path, "."), Err: err}
+}
+
+// findInvalidPath finds the path to the most specific item in a config file
+// that cannot be parsed on its own. Only options, tasks, and run items are
+// checked, as these are where validation takes place.
+func findInvalidPath(text []byte) []string {
+	var doc yaml.MapSlice
+	if err := yaml.Unmarshal(text, &doc); err != nil {
+		return nil
+	}
+
+	for _, item := range doc {
+		switch item.Key {
+		case "options":
+			for _, opt := range asMapSlice(item.Value) {
+				if path := findInvalidOption(opt); path != nil {
+					return append([]string{"options"}, path...)
+				}
+			}
+		case "tasks":
+			for _, t := range asMapSlice(item.Value) {
+				if path := findInvalidTask(t); path != nil {
+					return append([]string{"tasks"}, path...)
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+func findInvalidTask(item yaml.MapItem) []string {
+	name := fmt.Sprint(item.Key)
+
+	for _, field := range asMapSlice(item.Value) {
+		switch field.Key {
+		case "options":
+			for _, opt := range asMapSlice(field.Value) {
+				if path := findInvalidOption(opt); path != nil {
+					return append([]string{name, "options"}, path...)
+				}
+			}
+		case "run":
+			items, ok := field.Value.([]interface{})
+			if !ok {
+				if isInvalid(field.Value, new(run.Run)) {
+					return []string{name, "run"}
+				}
+				continue
+			}
+
+			for i, r := range items {
+				if isInvalid(r, new(run.Run)) {
+					return []string{name, "run", strconv.Itoa(i)}
+				}
+			}
+		}
+	}
+
+	if isInvalid(item.Value, new(task.Task)) {
+		return []string{name}
+	}
+
+	return nil
+}
+
+func findInvalidOption(item yaml.MapItem) []string {
+	name := fmt.Sprint(item.Key)
+
+	for _, field := range asMapSlice(item.Value) {
+		if field.Key != "default" {
+			continue
+		}
+
+		values, ok := field.Value.([]interface{})
+		if !ok {
+			continue
+		}
+
+		for i, value := range values {
+			single := yaml.MapSlice{{Key: "default", Value: value}}
+			if isInvalid(single, new(option.Option)) {
+				return []string{name, "default", strconv.Itoa(i)}
+			}
+		}
+	}
+
+	if isInvalid(item.Value, new(option.Option)) {
+		return []string{name}
+	}
+
+	return nil
+}
+
+// isInvalid tells if a yaml value cannot be parsed into the target.
+func isInvalid(value interface{}, target interface{}) bool {
+	text, err := yaml.Marshal(value)
+	if err != nil {
+		return true
+	}
+
+	return yaml.UnmarshalStrict(text, target) != nil
+}
+
+func asMapSlice(value interface{}) yaml.MapSlice {
+	ms, _ := value.(yaml.MapSlice)
+	return ms
+}
@@ -0,0 +1,161 @@
+package config
+
+import (
+	"errors"
+	"testing"
+)
+
+var parseerrortests = []struct {
+	desc    string
+	cfgText string
+	line    int
+	column  int
+	path    string
+}{
+	{
+		"invalid global option",
+		`
+options:
+  foo:
+    short: ab
+`,
+		3, 3, "options.foo",
+	},
+	{
+		"invalid task option",
+		`
+tasks:
+  test:
+    options:
+      verbose:
+        private: true
+        required: true
+`,
+		5, 7, "tasks.test.options.verbose",
+	},
+	{
+		"invalid option default",
+		`
+options:
+  foo:
+    default:
+      - value: bar
+      - value: bar
+        command: echo baz
+`,
+		6, 7, "options.foo.default.1",
+	},
+	{
+		"invalid run item",
+		`
+tasks:
+  test:
+    run:
+      - echo hi
+      - command: echo hi
+        task: other
+`,
+		6, 7, "tasks.test.run.1",
+	},
+	{
+		"unknown field",
+		`
+tasks:
+  test:
+    usage: hi
+    bogus: true
+`,
+		5, 5, "tasks.test.bogus",
+	},
+	{
+		"conflicting alias",
+		`
+tasks:
+  test:
+    aliases: t
+  other:
+    aliases: [o, test]
+`,
+		6, 5, "tasks.other.aliases.1",
+	},
+	{
+		"undefined default task",
+		`
+tasks:
+  test: {}
+default: tset
+`,
+		4, 1, "default",
+	},
+}
+
+func TestParse_errors(t *testing.T) {
+	for _, tt := range parseerrortests {
+		_, err := Parse([]byte(tt.cfgText))
+		cfgErr, ok := err.(*Error)
+		if !ok {
+			t.Errorf("Parse() for %s: expected *Error, got %#v", tt.desc, err)
+			continue
+		}
+
+		if tt.line != cfgErr.Line || tt.column != cfgErr.Column || tt.path != cfgErr.Path {
+			t.Errorf(
+				"Parse() for %s: expected %d:%d %s, actual %d:%d %s",
+				tt.desc, tt.line, tt.column, tt.path,
+				cfgErr.Line, cfgErr.Column, cfgErr.Path,
+			)
+		}
+	}
+}
+
+var errorstringtests = []struct {
+	err      *Error
+	expected string
+}{
+	{
+		&Error{File: "tusk.yml", Line: 3, Column: 5, Path: "tasks.foo", Err: errors.New("oops")},
+		"tusk.yml:3:5: tasks.foo: oops",
+	},
+	{
+		&Error{File: "tusk.yml", Line: 3, Err: errors.New("oops")},
+		"tusk.yml:3: oops",
+	},
+	{
+		&Error{Line: 3, Column: 5, Path: "tasks.foo", Err: errors.New("oops")},
+		"line 3, column 5: tasks.foo: oops",
+	},
+	{
+		&Error{Path: "tasks.foo", Err: errors.New("oops")},
+		"tasks.foo: oops",
+	},
+}
+
+func TestError_Error(t *testing.T) {
+	for _, tt := range errorstringtests {
+		if actual := tt.err.Error(); tt.expected != actual {
+			t.Errorf("Error(): expected %q, actual %q", tt.expected, actual)
+		}
+	}
+}
+
+func TestWithFile(t *testing.T) {
+	original := &Error{Line: 1, Column: 1, Err: errors.New("oops")}
+	err := WithFile(original, "tusk.yml")
+
+	if expected, actual := "tusk.yml:1:1: oops", err.Error(); expected != actual {
+		t.Errorf("WithFile(): expected %q, actual %q", expected, actual)
+	}
+
+	if original.File != "" {
+		t.Errorf("WithFile(): original error was modified: %#v", original)
+	}
+
+	plain := errors.New("oops")
+	if err := WithFile(plain, "tusk.yml"); err != plain {
+		t.Errorf("WithFile(plain): expected error to be unchanged, got %#v", err)
+	}
+
+	if err := WithFile(err, "other.yml"); err.Error() != "tusk.yml:1:1: oops" {
+		t.Errorf("WithFile(): expected existing file to be kept, actual %q", err)
+	}
+}
@@ -1,15 +1,21 @@
 package config
 
 import (
+	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 )
 
 // DefaultFile is the default name for a config file.
 var DefaultFile = "tusk.yml"
 
+// FileNames are the names searched for when finding a config file. Since yaml
+// is a superset of json, every file is parsed the same way.
+var FileNames = []string{DefaultFile, "tusk.yaml", ".tusk.yml", "tusk.json"}
+
 // SearchForFile checks the working directory and every parent directory to
-// find a configuration file with the default name.
+// find a configuration file with any of the supported names.
 // This should be called when an explicit file is not passed in to determine
 // the full path to the relevant config file.
 func SearchForFile() (fullPath string, found bool, err error) {
@@ -30,15 +36,31 @@ func SearchForFile() (fullPath string, found bool, err error) {
 	return "", false, nil
 }
 
+// findFileInDir finds the config file in a directory. Since it would be
+// unclear which one to use, it is an error for more than one to exist.
 func findFileInDir(dirPath string) (fullPath string, found bool, err error) {
-
-	fullPath = filepath.Join(dirPath, DefaultFile)
-	if _, err := os.Stat(fullPath); err != nil {
-		if os.IsNotExist(err) {
-			return "", false, nil
+	var matches []string
+	for _, name := range FileNames {
+		path := filepath.Join(dirPath, name)
+		if _, err := os.Stat(path); err != nil {
+			if os.IsNotExist(err) {
+				continue
+			}
+			return "", false, err
 		}
-		return "", false, err
+
+		matches = append(matches, name)
 	}
 
-	return fullPath, true, nil
+	switch len(matches) {
+	case 0:
+		return "", false, nil
+	case 1:
+		return filepath.Join(dirPath, matches[0]), true, nil
+	default:
+		return "", false, fmt.Errorf(
+			"multiple config files found in %s: %s",
+			dirPath, strings.Join(matches, ", "),
+		)
+	}
 }
@@ -0,0 +1,58 @@
+package config
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+var findfiletests = []struct {
+	files    []string
+	expected string
+	found    bool
+	err      bool
+}{
+	{nil, "", false, false},
+	{[]string{"other.yml"}, "", false, false},
+	{[]string{"tusk.yml"}, "tusk.yml", true, false},
+	{[]string{"tusk.yaml"}, "tusk.yaml", true, false},
+	{[]string{".tusk.yml"}, ".tusk.yml", true, false},
+	{[]string{"tusk.json"}, "tusk.json", true, false},
+	{[]string{"tusk.yml", "tusk.yaml"}, "", false, true},
+	{[]string{".tusk.yml", "tusk.json"}, "", false, true},
+}
+
+func TestFindFileInDir(t *testing.T) {
+	for _, tt := range findfiletests {
+		dir, err := ioutil.TempDir("", "tusk-find")
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+		defer os.RemoveAll(dir) // nolint: errcheck
+
+		for _, name := range tt.files {
+			if err := ioutil.WriteFile(filepath.Join(dir, name), nil, 0644); err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+		}
+
+		fullPath, found, err := findFileInDir(dir)
+		if tt.err != (err != nil) {
+			t.Errorf("findFileInDir() with %v: expected error %t, actual %v", tt.files, tt.err, err)
+			continue
+		}
+
+		expected := ""
+		if tt.expected != "" {
+			expected = filepath.Join(dir, tt.expected)
+		}
+
+		if expected != fullPath || tt.found != found {
+			t.Errorf(
+				"findFileInDir() with %v: expected (%q, %t), actual (%q, %t)",
+				tt.files, expected, tt.found, fullPath, found,
+			)
+		}
+	}
+}
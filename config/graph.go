@@ -0,0 +1,225 @@
+package config
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/rliebz/tusk/config/option"
+	"github.com/rliebz/tusk/config/task"
+)
+
+// Graph is the graph of tasks, the sub-tasks they run, and the options they
+// use. Each edge points from a task or option to something it depends on.
+type Graph struct {
+	Nodes []*GraphNode
+	Edges []GraphEdge
+
+	tasks   map[*task.Task]*GraphNode
+	options map[*option.Option]*GraphNode
+	edges   map[GraphEdge]bool
+}
+
+// GraphNode is a task or option in a graph.
+type GraphNode struct {
+	ID     string
+	Name   string
+	Option bool
+}
+
+// GraphEdge is a dependency of one node on another.
+type GraphEdge struct {
+	From *GraphNode
+	To   *GraphNode
+}
+
+// Graph returns the graph of a task, including every sub-task and option it
+// depends on. If no task name is passed, the graph of every task is returned.
+func (c *Config) Graph(taskName string) (*Graph, error) {
+	g := &Graph{
+		tasks:   make(map[*task.Task]*GraphNode),
+		options: make(map[*option.Option]*GraphNode),
+		edges:   make(map[GraphEdge]bool),
+	}
+
+	var roots []*task.Task
+	if taskName != "" {
+		t, ok := c.FindTask(taskName)
+		if !ok {
+			return nil, fmt.Errorf(`could not find task "%s"`, taskName)
+		}
+		roots = append(roots, t)
+	} else {
+		var names []string
+		for name := range c.Tasks {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		for _, name := range names {
+			roots = append(roots, c.Tasks[name])
+		}
+	}
+
+	for _, t := range roots {
+		if _, err := g.addTask(c, t); err != nil {
+			return nil, err
+		}
+	}
+
+	return g, nil
+}
+
+// addTask adds a task to the graph along with its dependencies.
+func (g *Graph) addTask(cfg *Config, t *task.Task) (*GraphNode, error) {
+	if node, ok := g.tasks[t]; ok {
+		return node, nil
+	}
+
+	node := g.addNode(t.Name, false)
+	g.tasks[t] = node
+
+	if err := AddSubTasks(cfg, t); err != nil {
+		return nil, err
+	}
+
+	for _, subTask := range t.SubTasks {
+		subNode, err := g.addTask(cfg, subTask)
+		if err != nil {
+			return nil, err
+		}
+		g.addEdge(node, subNode)
+	}
+
+	// Options defined by the task are always used, since they become flags
+	var names []string
+	for name := range t.Options {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	// Option definitions are left out so that only the references made by the
+	// rest of the task are found here
+	own := *t
+	own.Options = nil
+	references, err := getDependencies(&own)
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(references)
+	names = append(names, references...)
+
+	candidates := cfg.optionCandidates(t)
+	for _, name := range names {
+		opt, ok := candidates[name]
+		if !ok {
+			continue
+		}
+
+		optNode, err := g.addOption(opt, candidates)
+		if err != nil {
+			return nil, err
+		}
+		g.addEdge(node, optNode)
+	}
+
+	return node, nil
+}
+
+// addOption adds an option to the graph along with the options it depends on.
+func (g *Graph) addOption(
+	opt *option.Option, candidates map[string]*option.Option,
+) (*GraphNode, error) {
+	if node, ok := g.options[opt]; ok {
+		return node, nil
+	}
+
+	node := g.addNode(opt.Name, true)
+	g.options[opt] = node
+
+	names, err := getDependencies(opt)
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		dependency, ok := candidates[name]
+		if !ok || dependency == opt {
+			continue
+		}
+
+		dependencyNode, err := g.addOption(dependency, candidates)
+		if err != nil {
+			return nil, err
+		}
+		g.addEdge(node, dependencyNode)
+	}
+
+	return node, nil
+}
+
+func (g *Graph) addNode(name string, isOption bool) *GraphNode {
+	node := &GraphNode{
+		ID:     fmt.Sprintf("n%d", len(g.Nodes)),
+		Name:   name,
+		Option: isOption,
+	}
+	g.Nodes = append(g.Nodes, node)
+	return node
+}
+
+func (g *Graph) addEdge(from, to *GraphNode) {
+	edge := GraphEdge{From: from, To: to}
+	if g.edges[edge] {
+		return
+	}
+
+	g.edges[edge] = true
+	g.Edges = append(g.Edges, edge)
+}
+
+// DOT returns the graph in the Graphviz DOT language. Tasks are drawn as
+// boxes and options as ellipses.
+func (g *Graph) DOT() string {
+	var buf bytes.Buffer
+
+	buf.WriteString("digraph tusk {\n")
+	buf.WriteString("  rankdir=LR;\n")
+	for _, node := range g.Nodes {
+		shape := "box"
+		if node.Option {
+			shape = "ellipse"
+		}
+		fmt.Fprintf(&buf, "  %s [label=%s, shape=%s];\n", node.ID, strconv.Quote(node.Name), shape)
+	}
+	for _, edge := range g.Edges {
+		fmt.Fprintf(&buf, "  %s -> %s;\n", edge.From.ID, edge.To.ID)
+	}
+	buf.WriteString("}\n")
+
+	return buf.String()
+}
+
+// Mermaid returns the graph as a Mermaid flowchart. Tasks are drawn as
+// rectangles and options as rounded stadiums.
+func (g *Graph) Mermaid() string {
+	var buf bytes.Buffer
+
+	buf.WriteString("flowchart LR\n")
+	for _, node := range g.Nodes {
+		label := `"` + strings.Replace(node.Name, `"`, "#quot;", -1) + `"`
+		if node.Option {
+			fmt.Fprintf(&buf, "  %s([%s])\n", node.ID, label)
+		} else {
+			fmt.Fprintf(&buf, "  %s[%s]\n", node.ID, label)
+		}
+	}
+	for _, edge := range g.Edges {
+		fmt.Fprintf(&buf, "  %s --> %s\n", edge.From.ID, edge.To.ID)
+	}
+
+	return buf.String()
+}
@@ -0,0 +1,97 @@
+package config
+
+import "testing"
+
+var graphCfgText = []byte(`options:
+  env:
+    default: dev
+  version:
+    default:
+      - when:
+          equal: {env: prod}
+        value: v1
+      - value: dev-build
+tasks:
+  lint:
+    run: echo lint
+  test:
+    options:
+      race:
+        type: bool
+    run: go test ${race}
+  release:
+    run:
+      - task: [lint, test]
+      - echo ${version}
+`)
+
+func TestConfig_Graph_DOT(t *testing.T) {
+	cfg, err := Parse(graphCfgText)
+	if err != nil {
+		t.Fatalf("Parse(): unexpected err: %s", err)
+	}
+
+	graph, err := cfg.Graph("release")
+	if err != nil {
+		t.Fatalf("Config.Graph(): unexpected err: %s", err)
+	}
+
+	expected := `digraph tusk {
+  rankdir=LR;
+  n0 [label="release", shape=box];
+  n1 [label="lint", shape=box];
+  n2 [label="test", shape=box];
+  n3 [label="race", shape=ellipse];
+  n4 [label="version", shape=ellipse];
+  n5 [label="env", shape=ellipse];
+  n0 -> n1;
+  n2 -> n3;
+  n0 -> n2;
+  n4 -> n5;
+  n0 -> n4;
+}
+`
+	if actual := graph.DOT(); expected != actual {
+		t.Errorf("Graph.DOT(): expected:\n%s\nactual:\n%s", expected, actual)
+	}
+}
+
+func TestConfig_Graph_Mermaid(t *testing.T) {
+	cfg, err := Parse(graphCfgText)
+	if err != nil {
+		t.Fatalf("Parse(): unexpected err: %s", err)
+	}
+
+	graph, err := cfg.Graph("")
+	if err != nil {
+		t.Fatalf("Config.Graph(): unexpected err: %s", err)
+	}
+
+	expected := `flowchart LR
+  n0["lint"]
+  n1["release"]
+  n2["test"]
+  n3(["race"])
+  n4(["version"])
+  n5(["env"])
+  n1 --> n0
+  n2 --> n3
+  n1 --> n2
+  n4 --> n5
+  n1 --> n4
+`
+	if actual := graph.Mermaid(); expected != actual {
+		t.Errorf("Graph.Mermaid(): expected:\n%s\nactual:\n%s", expected, actual)
+	}
+}
+
+func TestConfig_Graph_missing_task(t *testing.T) {
+	cfg, err := Parse(graphCfgText)
+	if err != nil {
+		t.Fatalf("Parse(): unexpected err: %s", err)
+	}
+
+	if _, err := cfg.Graph("fake"); err == nil {
+		t.Error(`Config.Graph("fake"): expected err, actual nil`)
+	}
+}
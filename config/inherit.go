@@ -0,0 +1,189 @@
+package config
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	yaml "gopkg.in/yaml.v2"
+)
+
+// File is a config file that has been read.
+type File struct {
+	Path string
+	Text []byte
+
+	// User is set for the config file that applies to every project.
+	User bool
+}
+
+// FindParents returns the config files that a config file inherits from,
+// nearest first. Each parent directory is searched as long as the last file
+// found sets `inherit: true`, stopping at the root of the repository.
+func FindParents(path string, text []byte) ([]File, error) {
+	dir, err := filepath.Abs(filepath.Dir(path))
+	if err != nil {
+		return nil, err
+	}
+
+	var parents []File
+	for inherits(text) && !isRepoRoot(dir) {
+		parentDir := filepath.Dir(dir)
+		if parentDir == dir {
+			break
+		}
+		dir = parentDir
+
+		parentPath, found, err := findFileInDir(dir)
+		if err != nil {
+			return nil, err
+		}
+		if !found {
+			continue
+		}
+
+		if text, err = ioutil.ReadFile(parentPath); err != nil {
+			return nil, err
+		}
+
+		parents = append(parents, File{Path: parentPath, Text: text})
+	}
+
+	return parents, nil
+}
+
+// FindUserFile finds the config file that applies to every project, which is
+// in the tusk directory of $XDG_CONFIG_HOME, or ~/.config by default.
+func FindUserFile() (File, bool, error) {
+	dir := os.Getenv("XDG_CONFIG_HOME")
+	if dir == "" {
+		home := os.Getenv("HOME")
+		if home == "" {
+			return File{}, false, nil
+		}
+		dir = filepath.Join(home, ".config")
+	}
+
+	path, found, err := findFileInDir(filepath.Join(dir, "tusk"))
+	if err != nil || !found {
+		return File{}, false, err
+	}
+
+	text, err := ioutil.ReadFile(path)
+	if err != nil {
+		return File{}, false, err
+	}
+
+	return File{Path: path, Text: text, User: true}, true, nil
+}
+
+// inherits tells if a config file inherits from the files in its parent
+// directories. Any errors are left to be reported when the file is parsed.
+func inherits(text []byte) bool {
+	var cfg struct {
+		Inherit bool
+	}
+
+	if err := yaml.Unmarshal(text, &cfg); err != nil {
+		return false
+	}
+
+	return cfg.Inherit
+}
+
+// isRepoRoot tells if a directory is the root of a git repository.
+func isRepoRoot(dir string) bool {
+	_, err := os.Stat(filepath.Join(dir, ".git"))
+	return err == nil
+}
+
+// Parse parses the config file along with every file it inherits from,
+// including the user config file.
+func (m *Metadata) Parse() (*Config, error) {
+	cfg, err := parseFile(m.CfgText, m.Directory)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, parent := range m.Parents {
+		// Tasks from the user config file are run in the current project
+		dir := filepath.Dir(parent.Path)
+		if parent.User {
+			dir = m.Directory
+		}
+
+		parentCfg, err := parseFile(parent.Text, dir)
+		if err != nil {
+			return nil, WithFile(err, parent.Path)
+		}
+
+		for _, t := range parentCfg.Tasks {
+			t.User = parent.User
+		}
+
+		cfg.inherit(parentCfg, parent.User)
+	}
+
+	if err := checkDefault(m.CfgText, cfg); err != nil {
+		return nil, err
+	}
+
+	return cfg, nil
+}
+
+// parseFile parses a config file without checking the default task, which
+// may be defined by a parent config file. Tasks are run in the directory
+// passed, if any.
+func parseFile(text []byte, dir string) (*Config, error) {
+	cfg, err := parse(text)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, t := range cfg.Tasks {
+		t.Dir = dir
+		for _, opt := range t.Options {
+			opt.Dir = dir
+		}
+	}
+
+	return cfg, nil
+}
+
+// inherit adds the tasks and global options of a parent config. Tasks and
+// options already defined take precedence over the parent's, except that the
+// default values of options in the user config file are kept as a fallback.
+func (c *Config) inherit(parent *Config, user bool) {
+	for name, t := range parent.Tasks {
+		if _, ok := c.FindTask(name); !ok {
+			c.Tasks[name] = t
+		}
+	}
+
+	for name, opt := range parent.Options {
+		existing, ok := c.Options[name]
+		switch {
+		case !ok:
+			c.Options[name] = opt
+		case user:
+			existing.AddFallback(opt)
+		}
+	}
+
+	if c.Default == "" {
+		c.Default = parent.Default
+	}
+
+	defined := make(map[string]bool)
+	for _, name := range c.optionOrder {
+		defined[name] = true
+	}
+
+	var order []string
+	for _, name := range parent.optionOrder {
+		if !defined[name] {
+			order = append(order, name)
+		}
+	}
+	c.optionOrder = append(order, c.optionOrder...)
+}
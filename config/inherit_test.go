@@ -0,0 +1,279 @@
+package config
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestFindParents(t *testing.T) {
+	root, err := ioutil.TempDir("", "tusk-inherit")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	defer os.RemoveAll(root) // nolint: errcheck
+
+	files := map[string]string{
+		"tusk.yml":                  "tasks: {}",
+		"repo/.git/HEAD":            "",
+		"repo/tusk.yml":             "inherit: true",
+		"repo/a/tusk.yml":           "tasks: {}",
+		"repo/a/b/c/tusk.yml":       "inherit: true",
+		"repo/d/tusk.yml":           "inherit: true",
+		"repo/d/e/tusk.yml":         "inherit: true",
+		"repo/standalone/tusk.yaml": "tasks: {}",
+	}
+	for name, content := range files {
+		path := filepath.Join(root, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+		if err := ioutil.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+	}
+
+	tests := []struct {
+		path     string
+		expected []string
+	}{
+		{"repo/a/b/c/tusk.yml", []string{"repo/a/tusk.yml"}},
+		{"repo/d/e/tusk.yml", []string{"repo/d/tusk.yml", "repo/tusk.yml"}},
+		{"repo/standalone/tusk.yaml", nil},
+		{"repo/tusk.yml", nil},
+	}
+
+	for _, tt := range tests {
+		path := filepath.Join(root, tt.path)
+		text, err := ioutil.ReadFile(path)
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+
+		parents, err := FindParents(path, text)
+		if err != nil {
+			t.Errorf("FindParents(%s): unexpected error: %s", tt.path, err)
+			continue
+		}
+
+		var actual []string
+		for _, parent := range parents {
+			rel, err := filepath.Rel(root, parent.Path)
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			actual = append(actual, rel)
+		}
+
+		if !reflect.DeepEqual(tt.expected, actual) {
+			t.Errorf("FindParents(%s): expected %v, actual %v", tt.path, tt.expected, actual)
+		}
+	}
+}
+
+func TestMetadata_Parse(t *testing.T) {
+	meta := &Metadata{
+		CfgText: []byte(`
+inherit: true
+options:
+  env: {default: prod}
+tasks:
+  test: {usage: child}
+`),
+		Directory: "/child",
+		Parents: []File{{
+			Path: "/parent/tusk.yml",
+			Text: []byte(`
+default: ci
+options:
+  env: {default: dev}
+  region: {default: us}
+tasks:
+  ci: {run: {task: test}}
+  test: {usage: parent}
+  build: {aliases: [b]}
+`),
+		}},
+	}
+
+	cfg, err := meta.Parse()
+	if err != nil {
+		t.Fatalf("Parse(): unexpected error: %s", err)
+	}
+
+	if cfg.Default != "ci" {
+		t.Errorf("Parse(): expected Default: ci, actual: %s", cfg.Default)
+	}
+
+	if usage := cfg.Tasks["test"].Usage; usage != "child" {
+		t.Errorf("Parse(): expected child task to take precedence, actual usage: %s", usage)
+	}
+
+	dirs := map[string]string{"test": "/child", "ci": "/parent", "build": "/parent"}
+	for name, expected := range dirs {
+		if actual := cfg.Tasks[name].Dir; expected != actual {
+			t.Errorf("Parse(): expected task %s Dir: %s, actual: %s", name, expected, actual)
+		}
+	}
+
+	if value := cfg.Options["env"].DefaultValues[0].Value; value != "prod" {
+		t.Errorf("Parse(): expected child option to take precedence, actual default: %s", value)
+	}
+
+	if _, ok := cfg.Options["region"]; !ok {
+		t.Error("Parse(): expected parent option region to be inherited")
+	}
+
+	if expected := []string{"region", "env"}; !reflect.DeepEqual(expected, cfg.optionOrder) {
+		t.Errorf("Parse(): expected option order %v, actual %v", expected, cfg.optionOrder)
+	}
+}
+
+func TestMetadata_Parse_parent_error(t *testing.T) {
+	meta := &Metadata{
+		CfgText: []byte("inherit: true"),
+		Parents: []File{{Path: "/parent/tusk.yml", Text: []byte("tasks: {foo: {bar: baz}}")}},
+	}
+
+	_, err := meta.Parse()
+	cfgErr, ok := err.(*Error)
+	if !ok {
+		t.Fatalf("Parse(): expected *Error, actual %#v", err)
+	}
+
+	if cfgErr.File != "/parent/tusk.yml" {
+		t.Errorf("Parse(): expected error in /parent/tusk.yml, actual %s", cfgErr.File)
+	}
+}
+
+func TestFindUserFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "tusk-user")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	defer os.RemoveAll(dir) // nolint: errcheck
+
+	defer os.Setenv("XDG_CONFIG_HOME", os.Getenv("XDG_CONFIG_HOME")) // nolint: errcheck
+	os.Setenv("XDG_CONFIG_HOME", dir)                                // nolint: errcheck, gas
+
+	if _, found, err := FindUserFile(); err != nil || found {
+		t.Errorf("FindUserFile(): expected no file, actual found: %t, err: %v", found, err)
+	}
+
+	path := filepath.Join(dir, "tusk", "tusk.yml")
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if err := ioutil.WriteFile(path, []byte("tasks: {}"), 0644); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	file, found, err := FindUserFile()
+	if err != nil || !found {
+		t.Fatalf("FindUserFile(): expected file, actual found: %t, err: %v", found, err)
+	}
+
+	if file.Path != path || !file.User {
+		t.Errorf("FindUserFile(): expected user file %s, actual %#v", path, file)
+	}
+}
+
+func TestMetadata_Parse_user(t *testing.T) {
+	meta := &Metadata{
+		CfgText:   []byte("options: {env: {}}\ntasks: {build: {}}"),
+		Directory: "/project",
+		Parents: []File{{
+			Path: "/home/.config/tusk/tusk.yml",
+			Text: []byte("options: {env: {default: dev}}\ntasks: {build: {}, helper: {}}"),
+			User: true,
+		}},
+	}
+
+	cfg, err := meta.Parse()
+	if err != nil {
+		t.Fatalf("Parse(): unexpected error: %s", err)
+	}
+
+	if cfg.Tasks["build"].User {
+		t.Error("Parse(): expected project task to take precedence over user task")
+	}
+
+	helper := cfg.Tasks["helper"]
+	if !helper.User || helper.Dir != "/project" {
+		t.Errorf("Parse(): expected user task run from /project, actual %#v", helper)
+	}
+
+	value, err := cfg.Options["env"].Evaluate()
+	if err != nil {
+		t.Fatalf("Option.Evaluate(): unexpected error: %s", err)
+	}
+
+	if value != "dev" {
+		t.Errorf(`Parse(): expected user default "dev" as a fallback, actual "%s"`, value)
+	}
+}
+
+func TestMetadata_Parse_option_dir(t *testing.T) {
+	parent, err := ioutil.TempDir("", "tusk-parent")
+	if err != nil {
+		t.Fatalf("ioutil.TempDir(): unexpected error: %s", err)
+	}
+	defer os.RemoveAll(parent) // nolint: errcheck
+
+	// Symbolic links such as /tmp on macOS are resolved by pwd
+	parent, err = filepath.EvalSymlinks(parent)
+	if err != nil {
+		t.Fatalf("filepath.EvalSymlinks(): unexpected error: %s", err)
+	}
+
+	child := filepath.Join(parent, "child")
+	if err = os.Mkdir(child, 0755); err != nil {
+		t.Fatalf("os.Mkdir(): unexpected error: %s", err)
+	}
+
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatalf("os.Getwd(): unexpected error: %s", err)
+	}
+	defer os.Chdir(wd) // nolint: errcheck
+	if err = os.Chdir(child); err != nil {
+		t.Fatalf("os.Chdir(): unexpected error: %s", err)
+	}
+
+	meta := &Metadata{
+		CfgText:   []byte("inherit: true"),
+		Directory: child,
+		Parents: []File{{
+			Path: filepath.Join(parent, "tusk.yml"),
+			Text: []byte(`
+tasks:
+  where:
+    options:
+      dir:
+        default:
+          command: pwd
+    run: echo ${dir}
+`),
+		}},
+	}
+
+	cfg, err := meta.Parse()
+	if err != nil {
+		t.Fatalf("Parse(): unexpected error: %s", err)
+	}
+
+	values, err := Interpolate(cfg, nil, "where")
+	if err != nil {
+		t.Fatalf("Interpolate(): unexpected error: %s", err)
+	}
+
+	if values["dir"] != parent {
+		t.Errorf("Interpolate(): expected option computed in %s, actual %s", parent, values["dir"])
+	}
+
+	if actual, err := os.Getwd(); err != nil || actual != child {
+		t.Errorf("Interpolate(): expected working directory %s, actual %s", child, actual)
+	}
+}
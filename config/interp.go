@@ -3,17 +3,21 @@ package config
 import (
 	"fmt"
 
+	"github.com/pkg/errors"
 	"github.com/rliebz/tusk/config/option"
 	"github.com/rliebz/tusk/config/task"
 	"github.com/rliebz/tusk/interp"
-	yaml "gopkg.in/yaml.v2"
+	"github.com/rliebz/tusk/ui"
 )
 
-// Interpolate evaluates the variables given and returns interpolated text.
+// Interpolate evaluates the options required by a task and returns their
+// values.
 //
-// cfgText should be a valid, uninterpolated yaml configuration. While
-// there is currently no distinct validation phase, it is likely that this
-// function would return an error for invalid interpolation syntax.
+// cfg should be a freshly parsed configuration. Each required option has its
+// own fields interpolated with the values of the options evaluated before it,
+// in the order they are defined, before being evaluated. Only string fields
+// of the parsed config are modified, so values are never re-parsed as yaml.
+// Run items are interpolated later when the task is executed.
 //
 // passed is a map of variable names to values, which are the values of the
 // flags that were passed directly by CLI. These will be used in determining
@@ -22,51 +26,26 @@ import (
 //
 // taskName is the name of the task being run. This is used to determine the
 // list of options which require interpolation.
-func Interpolate(cfgText []byte, passed map[string]string, taskName string) ([]byte, map[string]string, error) {
-
-	options := make(map[string]string)
-
-	ordered, err := getOrderedOpts(cfgText)
-	if err != nil {
-		return nil, nil, err
-	}
-
-	required, err := getRequiredOpts(cfgText, taskName)
-	if err != nil {
-		return nil, nil, err
-	}
-
-	for _, optName := range ordered {
-		for _, r := range required {
-			if r != optName {
-				continue
-			}
+func Interpolate(cfg *Config, passed map[string]string, taskName string) (map[string]string, error) {
+	return interpolate(cfg, passed, taskName, false)
+}
 
-			value, err := getOptValue(cfgText, passed, options, optName, taskName)
-			if err != nil {
-				return nil, nil, err
-			}
+// Resolve evaluates the options required by a task the same way as
+// Interpolate, except that an option that cannot get a value is left empty
+// instead of returning an error. The resolution of each option, including the
+// ones that failed, can then be explained.
+func Resolve(cfg *Config, passed map[string]string, taskName string) (map[string]string, error) {
+	return interpolate(cfg, passed, taskName, true)
+}
 
-			options[optName] = value
+func interpolate(
+	cfg *Config, passed map[string]string, taskName string, tolerant bool,
+) (map[string]string, error) {
 
-			cfgText, err = interp.Interpolate(cfgText, optName, value)
-			if err != nil {
-				return nil, nil, err
-			}
-		}
-	}
+	values := make(map[string]string)
 
-	return interp.Escape(cfgText), options, nil
-}
-
-func getRequiredOpts(cfgText []byte, taskName string) ([]string, error) {
 	if taskName == "" {
-		return []string{}, nil
-	}
-
-	cfg, err := Parse(cfgText)
-	if err != nil {
-		return nil, err
+		return values, nil
 	}
 
 	t, ok := cfg.Tasks[taskName]
@@ -74,7 +53,7 @@ func getRequiredOpts(cfgText []byte, taskName string) ([]string, error) {
 		return nil, fmt.Errorf(`could not find task "%s"`, taskName)
 	}
 
-	if err = AddSubTasks(cfg, t); err != nil {
+	if err := AddSubTasks(cfg, t); err != nil {
 		return nil, err
 	}
 
@@ -83,87 +62,62 @@ func getRequiredOpts(cfgText []byte, taskName string) ([]string, error) {
 		return nil, err
 	}
 
-	var output []string
-	for _, opt := range required {
-		output = append(output, opt.Name)
-	}
-
-	return output, nil
-}
+	for _, optName := range cfg.optionOrder {
+		if _, ok := values[optName]; ok {
+			continue
+		}
 
-// getOrderedOpts returns a list of options in the order they appear.
-func getOrderedOpts(cfgText []byte) ([]string, error) {
+		if !containsOption(required, optName) {
+			continue
+		}
 
-	ordered := new(struct {
-		Options yaml.MapSlice
-		Tasks   yaml.MapSlice
-	})
+		opt, err := getOpt(cfg, optName, taskName)
+		if err != nil {
+			return nil, err
+		}
 
-	if err := yaml.Unmarshal(cfgText, ordered); err != nil {
-		return nil, err
-	}
+		// Global options are evaluated for the task being run
+		if cfg.Options[optName] == opt {
+			opt.Interpreter = t.Interpreter
+		}
 
-	allOpts := ordered.Options
+		context := fmt.Sprintf(`option "%s"`, optName)
+		warnings, err := interp.CheckUndefined(opt, values, context, t.Strict)
+		if err != nil {
+			return nil, err
+		}
+		for _, warning := range warnings {
+			ui.Warn(warning)
+		}
 
-	for _, mapslice := range ordered.Tasks {
-		for _, ms := range mapslice.Value.(yaml.MapSlice) {
-			name := ms.Key.(string)
-			if name != "options" {
-				continue
-			}
+		if err := interp.Struct(opt, values); err != nil {
+			return nil, errors.Wrapf(err, "could not interpolate option %s", optName)
+		}
 
-			allOpts = append(allOpts, ms.Value.(yaml.MapSlice)...)
+		opt.Vars = values
+		if valuePassed, ok := passed[optName]; ok {
+			opt.Passed = valuePassed
 		}
-	}
 
-	var output []string
-	for _, mapslice := range allOpts {
-		name, ok := mapslice.Key.(string)
-		if !ok {
-			return nil, fmt.Errorf("failed to assert name as string: %v", mapslice.Key)
+		value, err := opt.Evaluate()
+		if err != nil && !tolerant {
+			return nil, err
 		}
 
-		output = append(output, name)
+		values[optName] = value
 	}
 
-	return output, nil
+	return values, nil
 }
 
-func getOptValue(
-	cfgText []byte,
-	passed map[string]string,
-	options map[string]string,
-	optName string,
-	taskName string,
-) (string, error) {
-
-	cfg, err := Parse(cfgText)
-	if err != nil {
-		return "", err
-	}
-
-	t, ok := cfg.Tasks[taskName]
-	if !ok {
-		return "", fmt.Errorf(`could not find task "%s"`, taskName)
-	}
-
-	if err = AddSubTasks(cfg, t); err != nil {
-		return "", err
-	}
-
-	opt, err := getOpt(cfg, optName, taskName)
-	if err != nil {
-		return "", err
-	}
-
-	opt.Vars = options
-
-	valuePassed, ok := passed[optName]
-	if ok {
-		opt.Passed = valuePassed
+func containsOption(options []*option.Option, name string) bool {
+	for _, opt := range options {
+		if opt.Name == name {
+			return true
+		}
 	}
 
-	return opt.Evaluate()
+	return false
 }
 
 // getOpt gets an option from a Config by name. Task-specific options, sub-
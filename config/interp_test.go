@@ -2,6 +2,7 @@ package config
 
 import (
 	"fmt"
+	"reflect"
 	"testing"
 )
 
@@ -10,7 +11,7 @@ var interpolatetests = []struct {
 	cfgText  string
 	passed   map[string]string
 	taskName string
-	expected string
+	expected map[string]string
 }{
 	{
 		"happy path",
@@ -24,14 +25,7 @@ tasks:
 `,
 		map[string]string{},
 		"mytask",
-		`
-options:
-  foo:
-    default: bar
-tasks:
-  mytask:
-    run: echo bar
-`,
+		map[string]string{"foo": "bar"},
 	},
 
 	{
@@ -46,14 +40,7 @@ tasks:
 `,
 		map[string]string{"foo": "passed"},
 		"mytask",
-		`
-options:
-  foo:
-    default: bar
-tasks:
-  mytask:
-    run: echo passed
-`,
+		map[string]string{"foo": "passed"},
 	},
 
 	{
@@ -72,18 +59,8 @@ tasks:
 `,
 		map[string]string{},
 		"mytask",
-		`
-options:
-  foo:
-    default: foovalue
-  bar:
-    default: barvalue
-tasks:
-  mytask:
-    run: echo foovalue
-  unused:
-    run: echo ${bar}
-`},
+		map[string]string{"foo": "foovalue"},
+	},
 
 	{
 		"escaped interpolation over multiple iterations",
@@ -102,19 +79,8 @@ tasks:
 `,
 		map[string]string{},
 		"mytask",
-		`
-options:
-  foo:
-    default: foovalue
-  bar:
-    default: foovalue
-tasks:
-  pretask:
-    run: echo ${bar}
-  mytask:
-    run:
-      task: pretask
-`},
+		map[string]string{},
+	},
 
 	{
 		"no task specified",
@@ -128,14 +94,7 @@ tasks:
 `,
 		map[string]string{},
 		"",
-		`
-options:
-  foo:
-    default: bar
-tasks:
-  mytask:
-    run: echo ${foo}
-`,
+		map[string]string{},
 	},
 
 	{
@@ -152,16 +111,7 @@ tasks:
 `,
 		map[string]string{},
 		"mytask",
-		`
-options:
-  foo:
-    default: foovalue
-  bar:
-    default: foovalue
-tasks:
-  mytask:
-    run: echo foovalue
-`,
+		map[string]string{"foo": "foovalue", "bar": "foovalue"},
 	},
 
 	{
@@ -179,17 +129,7 @@ tasks:
 `,
 		map[string]string{},
 		"mytask",
-		`
-options:
-  foo:
-    default: foovalue
-tasks:
-  mytask:
-    options:
-      bar:
-        default: foovalue
-    run: echo foovalue
-`,
+		map[string]string{"foo": "foovalue", "bar": "foovalue"},
 	},
 
 	{
@@ -207,17 +147,7 @@ tasks:
 `,
 		map[string]string{},
 		"mytask",
-		`
-options:
-  foo:
-    default: foovalue
-tasks:
-  mytask:
-    options:
-      foo:
-        default: newvalue
-    run: echo newvalue
-`,
+		map[string]string{"foo": "newvalue"},
 	},
 
 	{
@@ -237,19 +167,7 @@ tasks:
 `,
 		map[string]string{},
 		"mytask",
-		`
-tasks:
-  unused:
-    options:
-      foo:
-        default: foovalue
-    run: echo barvalue
-  mytask:
-    options:
-      foo:
-        default: barvalue
-    run: echo barvalue
-`,
+		map[string]string{"foo": "barvalue"},
 	},
 
 	{
@@ -268,18 +186,7 @@ tasks:
 `,
 		map[string]string{},
 		"mytask",
-		`
-options:
-  foo:
-    default: foovalue
-
-tasks:
-  pretask:
-    run: echo foovalue
-  mytask:
-    run:
-      task: pretask
-`,
+		map[string]string{"foo": "foovalue"},
 	},
 
 	{
@@ -301,21 +208,7 @@ tasks:
 `,
 		map[string]string{},
 		"mytask",
-		`
-options:
-  foo:
-    default: foovalue
-
-tasks:
-  roottask:
-    run: echo foovalue
-  pretask:
-    run:
-      task: roottask
-  mytask:
-    run:
-      task: pretask
-`,
+		map[string]string{"foo": "foovalue"},
 	},
 
 	{
@@ -337,21 +230,7 @@ tasks:
 `,
 		map[string]string{"foo": "passed"},
 		"mytask",
-		`
-options:
-  foo:
-    default: foovalue
-
-tasks:
-  roottask:
-    run: echo passed
-  pretask:
-    run:
-      task: roottask
-  mytask:
-    run:
-      task: pretask
-`,
+		map[string]string{"foo": "passed"},
 	},
 
 	{
@@ -372,20 +251,7 @@ tasks:
 `,
 		map[string]string{},
 		"mytask",
-		`
-tasks:
-  roottask:
-    options:
-      foo:
-        default: foovalue
-    run: echo foovalue
-  pretask:
-    run:
-      task: roottask
-  mytask:
-    run:
-      task: pretask
-`,
+		map[string]string{"foo": "foovalue"},
 	},
 
 	{
@@ -412,26 +278,25 @@ tasks:
 `,
 		map[string]string{},
 		"mytask",
+		map[string]string{"bar": "barvalue", "foo": ""},
+	},
+
+	{
+		"global option with task interpreter",
 		`
+interpreter: [sh, -c]
 options:
-  bar:
-    default: barvalue
-
+  foo:
+    default:
+      command: echo global
 tasks:
   mytask:
-    options:
-      foo:
-        default:
-          when:
-            equal:
-              foo: true
-          value: barvalue
-    run:
-      when:
-        equal:
-          foo: true
-      command: echo yo
+    interpreter: [sh, -c, 'echo task $0']
+    run: echo ${foo}
 `,
+		map[string]string{},
+		"mytask",
+		map[string]string{"foo": "task echo global"},
 	},
 
 	{
@@ -452,20 +317,7 @@ tasks:
 `,
 		map[string]string{},
 		"two",
-		`
-options:
-  foo:
-    default: foovalue
-
-tasks:
-  one:
-    run:
-      - command: echo foovalue
-  two:
-    run:
-      - command: echo foovalue
-      - task: one
-`,
+		map[string]string{"foo": "foovalue"},
 	},
 }
 
@@ -473,22 +325,26 @@ func TestInterpolate(t *testing.T) {
 	for _, tt := range interpolatetests {
 
 		errString := fmt.Sprintf(
-			"Interpolate(cfgText, passed, taskName) for %s:\n"+
+			"Interpolate(cfg, passed, taskName) for %s:\n"+
 				"cfgText: `%s`\npassed: %v\ntaskName: %s",
 			tt.testCase, tt.cfgText, tt.passed, tt.taskName,
 		)
 
-		actualBytes, _, err := Interpolate([]byte(tt.cfgText), tt.passed, tt.taskName)
+		cfg, err := Parse([]byte(tt.cfgText))
 		if err != nil {
-			t.Errorf("%s\nunexpected error: %s", errString, err)
+			t.Errorf("%s\nunexpected error parsing config: %s", errString, err)
 			continue
 		}
 
-		actual := string(actualBytes)
+		actual, err := Interpolate(cfg, tt.passed, tt.taskName)
+		if err != nil {
+			t.Errorf("%s\nunexpected error: %s", errString, err)
+			continue
+		}
 
-		if tt.expected != actual {
+		if !reflect.DeepEqual(tt.expected, actual) {
 			t.Errorf(
-				"%s\nexpected: `%s`\nactual: `%s`\n",
+				"%s\nexpected: %#v\nactual: %#v\n",
 				errString, tt.expected, actual,
 			)
 			continue
@@ -497,6 +353,42 @@ func TestInterpolate(t *testing.T) {
 	}
 }
 
+func TestInterpolate_special_characters(t *testing.T) {
+	cfgText := `
+options:
+  foo:
+    default: bar
+  bar:
+    default: "${foo}: $${foo}"
+tasks:
+  mytask:
+    run: echo ${bar}
+`
+
+	cfg, err := Parse([]byte(cfgText))
+	if err != nil {
+		t.Fatalf("Parse(cfgText): unexpected error: %s", err)
+	}
+
+	passed := map[string]string{"foo": "'quoted': \"value\"\nnext: ${bar}"}
+	actual, err := Interpolate(cfg, passed, "mytask")
+	if err != nil {
+		t.Fatalf("Interpolate(cfg, ...): unexpected error: %s", err)
+	}
+
+	expected := map[string]string{
+		"foo": passed["foo"],
+		"bar": passed["foo"] + ": ${foo}",
+	}
+
+	if !reflect.DeepEqual(expected, actual) {
+		t.Errorf(
+			"Interpolate(cfg, ...):\nexpected: %#v\nactual: %#v",
+			expected, actual,
+		)
+	}
+}
+
 func TestInterpolate_no_redefining_sub_tasks(t *testing.T) {
 
 	cfgText := `
@@ -513,8 +405,57 @@ tasks:
       task: one
   `
 
-	if _, _, err := Interpolate([]byte(cfgText), nil, "foo"); err == nil {
-		t.Errorf("Interpolate(cfgText, ...): expected error, got nil")
+	cfg, err := Parse([]byte(cfgText))
+	if err != nil {
+		t.Fatalf("Parse(cfgText): unexpected error: %s", err)
+	}
+
+	if _, err := Interpolate(cfg, nil, "two"); err == nil {
+		t.Errorf("Interpolate(cfg, ...): expected error, got nil")
 	}
 
 }
+
+func TestResolve_required(t *testing.T) {
+	cfgText := `
+options:
+  env:
+    required: true
+  region:
+    default: ${env}-east
+tasks:
+  mytask:
+    run: echo ${env} ${region}
+`
+
+	cfg, err := Parse([]byte(cfgText))
+	if err != nil {
+		t.Fatalf("Parse(cfgText): unexpected error: %s", err)
+	}
+
+	if _, err = Interpolate(cfg, nil, "mytask"); err == nil {
+		t.Fatal("Interpolate(cfg, ...): expected error, got nil")
+	}
+
+	cfg, err = Parse([]byte(cfgText))
+	if err != nil {
+		t.Fatalf("Parse(cfgText): unexpected error: %s", err)
+	}
+
+	actual, err := Resolve(cfg, nil, "mytask")
+	if err != nil {
+		t.Fatalf("Resolve(cfg, ...): unexpected error: %s", err)
+	}
+
+	expected := map[string]string{"env": "", "region": "-east"}
+	if !reflect.DeepEqual(expected, actual) {
+		t.Errorf(
+			"Resolve(cfg, ...):\nexpected: %#v\nactual: %#v",
+			expected, actual,
+		)
+	}
+
+	if resolution := cfg.Options["env"].Resolution; resolution == nil || resolution.Err == nil {
+		t.Errorf(`Resolve(cfg, ...): expected option "env" to record an error`)
+	}
+}
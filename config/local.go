@@ -0,0 +1,87 @@
+package config
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	yaml "gopkg.in/yaml.v2"
+)
+
+// LocalFileNames are the names of the file that sets option values for a
+// single user of a project, which should not be checked in.
+var LocalFileNames = []string{"tusk.local.yml", ".tuskrc"}
+
+// FindLocalValues reads the option values from the local values file in a
+// directory. If the file does not exist, an empty path is returned.
+func FindLocalValues(dir string) (path string, values map[string]string, err error) {
+	var matches []string
+	for _, name := range LocalFileNames {
+		if _, err := os.Stat(filepath.Join(dir, name)); err != nil {
+			if os.IsNotExist(err) {
+				continue
+			}
+			return "", nil, err
+		}
+
+		matches = append(matches, name)
+	}
+
+	switch len(matches) {
+	case 0:
+		return "", nil, nil
+	case 1:
+		path = filepath.Join(dir, matches[0])
+	default:
+		return "", nil, fmt.Errorf(
+			"multiple local values files found in %s: %s",
+			dir, strings.Join(matches, ", "),
+		)
+	}
+
+	text, err := ioutil.ReadFile(path)
+	if err != nil {
+		return "", nil, err
+	}
+
+	if err := yaml.UnmarshalStrict(text, &values); err != nil {
+		return "", nil, WithFile(locateError(text, err), path)
+	}
+
+	return path, values, nil
+}
+
+// SetLocalValues sets the value from the local values file for every option
+// with a matching name. The names that do not match any option are returned.
+func (c *Config) SetLocalValues(values map[string]string) []string {
+	found := make(map[string]bool)
+
+	for name, opt := range c.Options {
+		if value, ok := values[name]; ok {
+			opt.Local = value
+			found[name] = true
+		}
+	}
+
+	for _, t := range c.Tasks {
+		for name, opt := range t.Options {
+			if value, ok := values[name]; ok {
+				opt.Local = value
+				found[name] = true
+			}
+		}
+	}
+
+	var unknown []string
+	for name := range values {
+		if !found[name] {
+			unknown = append(unknown, name)
+		}
+	}
+	sort.Strings(unknown)
+
+	return unknown
+}
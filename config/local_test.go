@@ -0,0 +1,98 @@
+package config
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+var localvaluestests = []struct {
+	files    map[string]string
+	expected map[string]string
+	path     string
+	err      bool
+}{
+	{nil, nil, "", false},
+	{
+		map[string]string{"tusk.local.yml": "db-host: localhost\nverbose: true"},
+		map[string]string{"db-host": "localhost", "verbose": "true"},
+		"tusk.local.yml",
+		false,
+	},
+	{
+		map[string]string{".tuskrc": "port: 8080"},
+		map[string]string{"port": "8080"},
+		".tuskrc",
+		false,
+	},
+	{map[string]string{"tusk.local.yml": "", ".tuskrc": ""}, nil, "", true},
+	{map[string]string{"tusk.local.yml": "db-host: [a, b]"}, nil, "", true},
+}
+
+func TestFindLocalValues(t *testing.T) {
+	for _, tt := range localvaluestests {
+		dir, err := ioutil.TempDir("", "tusk-local")
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+		defer os.RemoveAll(dir) // nolint: errcheck
+
+		for name, content := range tt.files {
+			if err := ioutil.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+		}
+
+		path, values, err := FindLocalValues(dir)
+		if tt.err != (err != nil) {
+			t.Errorf("FindLocalValues() with %v: expected error %t, actual %v", tt.files, tt.err, err)
+			continue
+		}
+
+		expectedPath := ""
+		if tt.path != "" {
+			expectedPath = filepath.Join(dir, tt.path)
+		}
+
+		if expectedPath != path || !reflect.DeepEqual(tt.expected, values) {
+			t.Errorf(
+				"FindLocalValues() with %v: expected (%q, %v), actual (%q, %v)",
+				tt.files, expectedPath, tt.expected, path, values,
+			)
+		}
+	}
+}
+
+func TestConfig_SetLocalValues(t *testing.T) {
+	cfg, err := Parse([]byte(`
+options:
+  global: {}
+tasks:
+  one:
+    options:
+      local: {}
+`))
+	if err != nil {
+		t.Fatalf("Parse(): unexpected error: %s", err)
+	}
+
+	unknown := cfg.SetLocalValues(map[string]string{
+		"global": "a",
+		"local":  "b",
+		"typo":   "c",
+	})
+
+	if expected := []string{"typo"}; !reflect.DeepEqual(expected, unknown) {
+		t.Errorf("SetLocalValues(): expected unknown names %v, actual %v", expected, unknown)
+	}
+
+	if actual := cfg.Options["global"].Local; actual != "a" {
+		t.Errorf(`SetLocalValues(): expected global option value "a", actual "%s"`, actual)
+	}
+
+	if actual := cfg.Tasks["one"].Options["local"].Local; actual != "b" {
+		t.Errorf(`SetLocalValues(): expected task option value "b", actual "%s"`, actual)
+	}
+}
@@ -0,0 +1,55 @@
+package option
+
+import (
+	"errors"
+	"strings"
+)
+
+// Complete defines how shell completion suggests values for an option.
+type Complete struct {
+	// Command is run to list the suggested values, one per line.
+	Command string
+	// Directories suggests only directories instead of all files.
+	Directories bool
+}
+
+// UnmarshalYAML ensures that exactly one completion source is defined.
+func (c *Complete) UnmarshalYAML(unmarshal func(interface{}) error) error {
+
+	type completeType Complete // Use new type to avoid recursion
+	if err := unmarshal((*completeType)(c)); err != nil {
+		return err
+	}
+
+	if c.Command != "" && c.Directories {
+		return errors.New("complete command and directories are both defined")
+	}
+
+	if c.Command == "" && !c.Directories {
+		return errors.New("complete requires a command or directories")
+	}
+
+	return nil
+}
+
+// Suggestions returns the values suggested for an option by shell completion,
+// either from its list of values or from the output of its complete command.
+func (o *Option) Suggestions() ([]string, error) {
+	if len(o.Values) > 0 || o.Complete == nil || o.Complete.Command == "" {
+		return o.Values, nil
+	}
+
+	out, err := o.Interpreter.Command(o.Complete.Command).Output()
+	if err != nil {
+		return nil, err
+	}
+
+	var suggestions []string
+	for _, line := range strings.Split(string(out), "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			suggestions = append(suggestions, line)
+		}
+	}
+
+	return suggestions, nil
+}
@@ -0,0 +1,45 @@
+package option
+
+import (
+	"reflect"
+	"testing"
+)
+
+var suggestionstests = []struct {
+	desc     string
+	option   Option
+	expected []string
+}{
+	{"no values", Option{}, nil},
+	{"values", Option{Values: []string{"a", "b"}}, []string{"a", "b"}},
+	{
+		"command",
+		Option{Complete: &Complete{Command: "printf 'one\\n\\n  two  \\n'"}},
+		[]string{"one", "two"},
+	},
+	{"directories", Option{Complete: &Complete{Directories: true}}, nil},
+}
+
+func TestOption_Suggestions(t *testing.T) {
+	for _, tt := range suggestionstests {
+		actual, err := tt.option.Suggestions()
+		if err != nil {
+			t.Errorf("Suggestions() for %s: unexpected error: %s", tt.desc, err)
+			continue
+		}
+
+		if !reflect.DeepEqual(tt.expected, actual) {
+			t.Errorf(
+				"Suggestions() for %s: expected %#v, actual %#v",
+				tt.desc, tt.expected, actual,
+			)
+		}
+	}
+}
+
+func TestOption_Suggestions_error(t *testing.T) {
+	o := Option{Complete: &Complete{Command: "exit 1"}}
+	if _, err := o.Suggestions(); err == nil {
+		t.Error("Suggestions(): expected error, actual nil")
+	}
+}
@@ -0,0 +1,52 @@
+package option
+
+import "fmt"
+
+// Resolution describes where the value of an option came from.
+type Resolution struct {
+	// Source is where the value came from, such as a flag or a default.
+	Source string
+	// Skipped lists the sources checked before the one used.
+	Skipped []SkippedSource
+	// Err is the error that kept the option from getting a value, if any.
+	Err error
+}
+
+// SkippedSource is a source that was checked but not used for a value.
+type SkippedSource struct {
+	Source string
+	Reason string
+}
+
+func (r *Resolution) use(format string, a ...interface{}) {
+	r.Source = fmt.Sprintf(format, a...)
+}
+
+func (r *Resolution) skip(source string, reason string) {
+	r.Skipped = append(r.Skipped, SkippedSource{Source: source, Reason: reason})
+}
+
+// Explain returns a description of how the option got its value, one line
+// per source checked.
+func (o *Option) Explain() []string {
+	if o.Resolution == nil {
+		return []string{fmt.Sprintf(`option "%s" has not been evaluated`, o.Name)}
+	}
+
+	lines := []string{fmt.Sprintf(
+		`option "%s" is "%s" from %s`, o.Name, o.cacheValue, o.Resolution.Source,
+	)}
+	if o.Resolution.Err != nil {
+		lines[0] = fmt.Sprintf(`option "%s" has no value: %s`, o.Name, o.Resolution.Err)
+	}
+
+	for _, skipped := range o.Resolution.Skipped {
+		lines = append(lines, fmt.Sprintf("  skipped %s: %s", skipped.Source, skipped.Reason))
+	}
+
+	if o.Export != "" {
+		lines = append(lines, fmt.Sprintf("  exported as %s", o.Export))
+	}
+
+	return lines
+}
@@ -0,0 +1,102 @@
+package option
+
+import (
+	"os"
+	"reflect"
+	"testing"
+
+	"github.com/rliebz/tusk/config/when"
+)
+
+var explaintests = []struct {
+	desc     string
+	option   Option
+	expected []string
+}{
+	{
+		"not evaluated",
+		Option{Name: "foo"},
+		[]string{`option "foo" has not been evaluated`},
+	},
+	{
+		"flag",
+		Option{Name: "foo", Passed: "passed", Export: "FOO"},
+		[]string{
+			`option "foo" is "passed" from flag --foo`,
+			"  exported as FOO",
+		},
+	},
+	{
+		"environment variable",
+		Option{Name: "foo", Environment: "EXPLAIN_SET_VAR"},
+		[]string{
+			`option "foo" is "env" from environment variable EXPLAIN_SET_VAR`,
+			"  skipped flag --foo: not passed",
+		},
+	},
+	{
+		"local value",
+		Option{Name: "foo", Environment: "EXPLAIN_UNSET_VAR", Local: "local"},
+		[]string{
+			`option "foo" is "local" from local values file`,
+			"  skipped flag --foo: not passed",
+			"  skipped environment variable EXPLAIN_UNSET_VAR: not set",
+		},
+	},
+	{
+		"default candidates",
+		Option{
+			Name: "foo",
+			DefaultValues: valueList{
+				{When: when.When{Exists: []string{"fake-file"}}, Value: "skipped"},
+				{Command: "echo computed"},
+			},
+		},
+		[]string{
+			`option "foo" is "computed" from default #2, computed by command "echo computed"`,
+			"  skipped flag --foo: not passed",
+			"  skipped local values file: not set",
+			`  skipped default #1: file "fake-file" does not exist`,
+		},
+	},
+	{
+		"private zero value",
+		Option{Name: "foo", Private: true, Type: "bool", Passed: "true"},
+		[]string{
+			`option "foo" is "false" from the zero value, since no default applies`,
+			"  skipped flag, environment, and local values: option is private",
+		},
+	},
+	{
+		"required",
+		Option{Name: "foo", Required: true},
+		[]string{
+			`option "foo" has no value: no value passed for required option: foo`,
+			"  skipped flag --foo: not passed",
+			"  skipped local values file: not set",
+		},
+	},
+}
+
+func TestOption_Explain(t *testing.T) {
+	if err := os.Setenv("EXPLAIN_SET_VAR", "env"); err != nil {
+		t.Fatalf("unexpected err setting environment variable: %s", err)
+	}
+	defer os.Unsetenv("EXPLAIN_SET_VAR") // nolint: errcheck
+
+	for _, tt := range explaintests {
+		if tt.desc != "not evaluated" {
+			if _, err := tt.option.Evaluate(); err != nil && !tt.option.Required {
+				t.Errorf("Option.Evaluate() for %s: unexpected err: %s", tt.desc, err)
+				continue
+			}
+		}
+
+		if actual := tt.option.Explain(); !reflect.DeepEqual(tt.expected, actual) {
+			t.Errorf(
+				"Option.Explain() for %s:\nexpected: %q\nactual: %q",
+				tt.desc, tt.expected, actual,
+			)
+		}
+	}
+}
@@ -6,7 +6,10 @@ import (
 	"strings"
 
 	"github.com/pkg/errors"
+	"github.com/rliebz/tusk/config/marshal"
+	"github.com/rliebz/tusk/config/shell"
 	"github.com/rliebz/tusk/config/when"
+	"github.com/rliebz/tusk/ui"
 )
 
 // Option represents an abstract command line option.
@@ -18,16 +21,24 @@ type Option struct {
 	Private  bool
 	Required bool
 
+	// Used to suggest values in shell completion
+	Values   marshal.StringList
+	Complete *Complete
+
 	// Used to determine value
 	Environment   string
 	DefaultValues valueList `yaml:"default"`
 
 	// Computed members not specified in yaml file
-	Name       string            `yaml:"-"`
-	Passed     string            `yaml:"-"`
-	Vars       map[string]string `yaml:"-"`
-	cacheValue string            `yaml:"-"`
-	isCacheSet bool              `yaml:"-"`
+	Name        string            `yaml:"-"`
+	Dir         string            `yaml:"-"`
+	Passed      string            `yaml:"-"`
+	Local       string            `yaml:"-"`
+	Resolution  *Resolution       `yaml:"-"`
+	Vars        map[string]string `yaml:"-"`
+	Interpreter shell.Interpreter `yaml:"-"`
+	cacheValue  string            `yaml:"-"`
+	isCacheSet  bool              `yaml:"-"`
 }
 
 // Dependencies returns a list of options that are required explicitly.
@@ -72,6 +83,14 @@ func (o *Option) UnmarshalYAML(unmarshal func(interface{}) error) error {
 		return errors.New("default value defined for required option")
 	}
 
+	if len(o.Values) > 0 && o.Complete != nil {
+		return errors.New("values and complete are both defined")
+	}
+
+	if o.isBoolean() && (len(o.Values) > 0 || o.Complete != nil) {
+		return errors.New("completion values defined for boolean option")
+	}
+
 	return nil
 }
 
@@ -80,7 +99,8 @@ func (o *Option) UnmarshalYAML(unmarshal func(interface{}) error) error {
 // The order of priority is:
 //   1. Command-line option passed
 //   2. Environment variable set
-//   3. The first item in the default value list with a valid when clause
+//   3. Value set in the local values file
+//   4. The first item in the default value list with a valid when clause
 //
 // Values may also be cached to avoid re-running commands.
 func (o *Option) Evaluate() (string, error) {
@@ -90,10 +110,17 @@ func (o *Option) Evaluate() (string, error) {
 
 	value, err := o.getValue()
 	if err != nil {
+		if o.Resolution != nil {
+			o.Resolution.Err = err
+		}
 		return "", err
 	}
 
 	o.cache(value)
+	ui.OptionEvaluated(o.Name, value)
+	for _, line := range o.Explain() {
+		ui.Debug(line)
+	}
 
 	if err := o.setenv(value); err != nil {
 		return "", err
@@ -115,50 +142,138 @@ func (o *Option) getValue() (string, error) {
 		return o.cacheValue, nil
 	}
 
-	if !o.Private {
+	o.Resolution = new(Resolution)
+
+	if o.Private {
+		o.Resolution.skip("flag, environment, and local values", "option is private")
+	} else {
 		if o.Passed != "" {
+			o.Resolution.use("flag --%s", o.Name)
 			return o.Passed, nil
 		}
+		o.Resolution.skip("flag --"+o.Name, "not passed")
+
+		if o.Environment != "" {
+			envValue := os.Getenv(o.Environment)
+			if envValue != "" {
+				o.Resolution.use("environment variable %s", o.Environment)
+				return envValue, nil
+			}
+			o.Resolution.skip("environment variable "+o.Environment, "not set")
+		}
 
-		envValue := os.Getenv(o.Environment)
-		if envValue != "" {
-			return envValue, nil
+		if o.Local != "" {
+			o.Resolution.use("local values file")
+			return o.Local, nil
 		}
+		o.Resolution.skip("local values file", "not set")
+	}
+
+	// Default values are computed from the directory of the task, if any
+	var value string
+	var ok bool
+	err := inDir(o.Dir, func() (err error) {
+		value, ok, err = o.getDefaultValue()
+		return err
+	})
+	if err != nil || ok {
+		return value, err
 	}
 
 	if o.Required {
 		return "", fmt.Errorf("no value passed for required option: %s", o.Name)
 	}
 
-	return o.getDefaultValue()
+	return o.getZeroValue(), nil
 }
 
-func (o *Option) getDefaultValue() (string, error) {
+// getDefaultValue returns the first default value with a valid when clause,
+// and whether one was found.
+func (o *Option) getDefaultValue() (string, bool, error) {
+	count, fallbacks := 0, 0
 	for _, candidate := range o.DefaultValues {
-		if err := candidate.When.Validate(o.Vars); err != nil {
+		var source string
+		if candidate.fallback {
+			fallbacks++
+			source = fmt.Sprintf("user config default #%d", fallbacks)
+		} else {
+			count++
+			source = fmt.Sprintf("default #%d", count)
+		}
+
+		if err := candidate.When.Validate(o.Vars, o.Interpreter); err != nil {
 			if !when.IsFailedCondition(err) {
-				return "", err
+				return "", false, err
 			}
+			o.Resolution.skip(source, err.Error())
 			continue
 		}
 
-		value, err := candidate.commandValueOrDefault()
+		value, err := candidate.commandValueOrDefault(o.Interpreter)
 		if err != nil {
-			return "", errors.Wrapf(err, "could not compute value for option: %s", o.Name)
+			return "", false, errors.Wrapf(err, "could not compute value for option: %s", o.Name)
+		}
+
+		if candidate.Command != "" {
+			o.Resolution.use(`%s, computed by command "%s"`, source, candidate.Command)
+		} else {
+			o.Resolution.use(source)
 		}
 
-		return value, nil
+		return value, true, nil
 	}
 
+	return "", false, nil
+}
+
+func (o *Option) getZeroValue() string {
+	o.Resolution.use("the zero value, since no default applies")
+
 	if o.isNumeric() {
-		return "0", nil
+		return "0"
 	}
 
 	if o.isBoolean() {
-		return "false", nil
+		return "false"
+	}
+
+	return ""
+}
+
+// inDir calls a function from within a directory, then changes back to the
+// previous working directory. If no directory is passed, the working
+// directory is not changed.
+func inDir(dir string, f func() error) (err error) {
+	if dir == "" {
+		return f()
+	}
+
+	wd, err := os.Getwd()
+	if err != nil {
+		return err
 	}
 
-	return "", nil
+	if err := os.Chdir(dir); err != nil {
+		return err
+	}
+
+	defer func() {
+		if chdirErr := os.Chdir(wd); err == nil {
+			err = chdirErr
+		}
+	}()
+
+	return f()
+}
+
+// AddFallback adds the default values of an option with the same name from
+// the user config file. They are checked only when none of the option's own
+// default values apply, including for required options that were not passed.
+func (o *Option) AddFallback(fallback *Option) {
+	for _, candidate := range fallback.DefaultValues {
+		candidate.fallback = true
+		o.DefaultValues = append(o.DefaultValues, candidate)
+	}
 }
 
 func (o *Option) cache(value string) {
@@ -166,6 +281,11 @@ func (o *Option) cache(value string) {
 	o.cacheValue = value
 }
 
+// HasValidType tells if the option's type is supported.
+func (o *Option) HasValidType() bool {
+	return o.isNumeric() || o.isBoolean() || o.isString()
+}
+
 func (o *Option) isNumeric() bool {
 	switch strings.ToLower(o.Type) {
 	case "int", "integer", "float", "float64", "double":
@@ -183,3 +303,12 @@ func (o *Option) isBoolean() bool {
 		return false
 	}
 }
+
+func (o *Option) isString() bool {
+	switch strings.ToLower(o.Type) {
+	case "string", "":
+		return true
+	default:
+		return false
+	}
+}
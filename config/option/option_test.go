@@ -108,6 +108,33 @@ var valuetests = []struct {
 		},
 		"passed",
 	},
+	{
+		"local value only",
+		&Option{Local: "local"},
+		"local",
+	},
+	{
+		"environment variable over local value",
+		&Option{Environment: "OPTION_VAR", Local: "local"},
+		"option_val",
+	},
+	{
+		"local value over default",
+		&Option{
+			Local:         "local",
+			DefaultValues: valueList{{Value: "default"}},
+		},
+		"local",
+	},
+	{
+		"local value ignored for private option",
+		&Option{
+			Private:       true,
+			Local:         "local",
+			DefaultValues: valueList{{Value: "default"}},
+		},
+		"default",
+	},
 }
 
 func TestOption_Evaluate(t *testing.T) {
@@ -221,6 +248,45 @@ var evaluteTypeDefaultTests = []struct {
 	{"", ""},
 }
 
+func TestOption_AddFallback(t *testing.T) {
+	fallback := &Option{DefaultValues: valueList{{Value: "fallback"}}}
+
+	option := Option{
+		DefaultValues: valueList{
+			{When: when.When{Exists: []string{"fake-file"}}, Value: "skipped"},
+		},
+	}
+	option.AddFallback(fallback)
+
+	actual, err := option.Evaluate()
+	if err != nil {
+		t.Fatalf("Option.Evaluate(): unexpected error: %s", err)
+	}
+
+	if expected := "fallback"; expected != actual {
+		t.Errorf(`Option.Evaluate(): expected "%s", actual "%s"`, expected, actual)
+	}
+
+	if expected := "user config default #1"; option.Resolution.Source != expected {
+		t.Errorf(
+			`Option.Evaluate(): expected source "%s", actual "%s"`,
+			expected, option.Resolution.Source,
+		)
+	}
+
+	required := Option{Required: true}
+	required.AddFallback(fallback)
+
+	actual, err = required.Evaluate()
+	if err != nil {
+		t.Fatalf("Option.Evaluate() for required option: unexpected error: %s", err)
+	}
+
+	if expected := "fallback"; expected != actual {
+		t.Errorf(`Option.Evaluate(): expected "%s", actual "%s"`, expected, actual)
+	}
+}
+
 func TestOption_Evaluate_type_defaults(t *testing.T) {
 	for _, tt := range evaluteTypeDefaultTests {
 		opt := Option{Type: tt.typeName}
@@ -284,6 +350,22 @@ var unmarshalOptionErrorTests = []struct {
 		"required and default defined",
 		"{required: true, default: foo}",
 	},
+	{
+		"values and complete defined",
+		"{values: [a, b], complete: {directories: true}}",
+	},
+	{
+		"values defined for boolean",
+		"{type: bool, values: [a, b]}",
+	},
+	{
+		"complete command and directories defined",
+		"{complete: {command: ls, directories: true}}",
+	},
+	{
+		"complete without a source",
+		"{complete: {}}",
+	},
 }
 
 func TestOption_UnmarshalYAML_invalid_definitions(t *testing.T) {
@@ -2,10 +2,10 @@ package option
 
 import (
 	"fmt"
-	"os/exec"
 	"strings"
 
 	"github.com/rliebz/tusk/config/marshal"
+	"github.com/rliebz/tusk/config/shell"
 	"github.com/rliebz/tusk/config/when"
 )
 
@@ -13,13 +13,17 @@ type value struct {
 	When    when.When
 	Command string
 	Value   string
+
+	// fallback marks values added from the user config file
+	fallback bool
 }
 
 // commandValueOrDefault validates a content definition, then gets the value.
-func (v *value) commandValueOrDefault() (string, error) {
+// Commands are run with the interpreter passed.
+func (v *value) commandValueOrDefault(interpreter shell.Interpreter) (string, error) {
 
 	if v.Command != "" {
-		out, err := exec.Command("sh", "-c", v.Command).Output() // nolint: gas
+		out, err := interpreter.Command(v.Command).Output()
 		if err != nil {
 			return "", err
 		}
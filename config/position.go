@@ -0,0 +1,246 @@
+package config
+
+import (
+	"bufio"
+	"bytes"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var (
+	keyPattern   = regexp.MustCompile(`^("[^"]*"|'[^']*'|[^\s"'#{\[][^:#]*?)\s*:(\s+(.*))?$`)
+	blockPattern = regexp.MustCompile(`^[|>][-+0-9]*\s*(#.*)?$`)
+)
+
+// entry is a mapping key or sequence item found in yaml text.
+type entry struct {
+	line   int
+	indent int
+	key    string
+	isItem bool
+}
+
+// Locate returns the line and column of the value found at a path in yaml
+// text. Path elements are mapping keys, or indices for sequence items. If the
+// full path cannot be found, the position of the closest ancestor is returned,
+// or 0 if nothing in the path could be found.
+//
+// Only block-style yaml is understood, which means that values within flow
+// collections such as `{foo: bar}` will be reported at their parent's line,
+// even if the collection spans several lines. Anchors and aliases are not
+// followed, and keys are matched by their text, so keys that are written
+// differently from how they are parsed, such as with escape sequences, are
+// not found. If the text cannot be scanned, 0 is returned.
+func Locate(text []byte, path ...string) (line, column int) {
+	entries, err := scanEntries(text)
+	if err != nil {
+		return 0, 0
+	}
+
+	start, end, parentIndent := 0, len(entries), -1
+
+	for _, element := range path {
+		i, ok := findChild(entries[start:end], parentIndent, element)
+		if !ok {
+			break
+		}
+
+		i += start
+		found := entries[i]
+		line, column = found.line, found.indent+1
+
+		start = i + 1
+		end = start
+		for end < len(entries) && isWithin(entries[end], found) {
+			end++
+		}
+		parentIndent = found.indent
+	}
+
+	return line, column
+}
+
+// PathAt returns the path to the deepest value defined on or before a line in
+// yaml text. It is the inverse of Locate, with the same limits. If the text
+// cannot be scanned, nil is returned.
+func PathAt(text []byte, line int) []string {
+	entries, err := scanEntries(text)
+	if err != nil {
+		return nil
+	}
+
+	type node struct {
+		entry
+		element string
+		items   int
+	}
+
+	root := &node{entry: entry{indent: -1}}
+	stack := []*node{root}
+
+	for _, e := range entries {
+		if e.line > line {
+			break
+		}
+
+		for len(stack) > 1 && !isWithin(e, stack[len(stack)-1].entry) {
+			stack = stack[:len(stack)-1]
+		}
+
+		parent := stack[len(stack)-1]
+		n := &node{entry: e, element: e.key}
+		if e.isItem {
+			n.element = strconv.Itoa(parent.items)
+			parent.items++
+		}
+
+		stack = append(stack, n)
+	}
+
+	var path []string
+	for _, n := range stack[1:] {
+		path = append(path, n.element)
+	}
+
+	return path
+}
+
+// findChild finds the direct child of a parent matching a path element.
+func findChild(entries []entry, parentIndent int, element string) (int, bool) {
+	if len(entries) == 0 {
+		return 0, false
+	}
+
+	childIndent := entries[0].indent
+	index, err := strconv.Atoi(element)
+	isIndex := err == nil
+
+	count := 0
+	for i, e := range entries {
+		if e.indent != childIndent || e.indent < parentIndent {
+			continue
+		}
+
+		if isIndex && e.isItem {
+			if count == index {
+				return i, true
+			}
+			count++
+		}
+
+		if !isIndex && !e.isItem && e.key == element {
+			return i, true
+		}
+	}
+
+	return 0, false
+}
+
+// isWithin tells if an entry is nested within the value of a parent entry.
+// Sequences are allowed at the same indentation as their parent key.
+func isWithin(e entry, parent entry) bool {
+	if e.indent > parent.indent {
+		return true
+	}
+
+	return e.indent == parent.indent && e.isItem && !parent.isItem
+}
+
+// scanEntries finds every mapping key and sequence item in yaml text. Lines
+// within flow collections are skipped.
+func scanEntries(text []byte) ([]entry, error) {
+	var entries []entry
+
+	// A line may be as long as the whole text
+	scanner := bufio.NewScanner(bytes.NewReader(text))
+	scanner.Buffer(make([]byte, 0, 4096), len(text)+1)
+
+	blockIndent, depth := -1, 0
+	for lineNumber := 1; scanner.Scan(); lineNumber++ {
+		raw := scanner.Text()
+		content := strings.TrimLeft(raw, " ")
+		indent := len(raw) - len(content)
+
+		if depth > 0 {
+			depth += bracketDepth(content)
+			if depth < 0 {
+				depth = 0
+			}
+			continue
+		}
+
+		if blockIndent >= 0 {
+			if content == "" || indent > blockIndent {
+				continue
+			}
+			blockIndent = -1
+		}
+
+		if content == "" || strings.HasPrefix(content, "#") || strings.HasPrefix(content, "---") {
+			continue
+		}
+
+		for content == "-" || strings.HasPrefix(content, "- ") {
+			entries = append(entries, entry{line: lineNumber, indent: indent, isItem: true})
+			trimmed := strings.TrimLeft(content[1:], " ")
+			indent += len(content) - len(trimmed)
+			content = trimmed
+		}
+
+		groups := keyPattern.FindStringSubmatch(content)
+		if groups == nil {
+			depth = flowDepth(content)
+			continue
+		}
+
+		entries = append(entries, entry{
+			line:   lineNumber,
+			indent: indent,
+			key:    strings.Trim(groups[1], `"'`),
+		})
+
+		if blockPattern.MatchString(groups[3]) {
+			blockIndent = indent
+		}
+		depth = flowDepth(groups[3])
+	}
+
+	return entries, scanner.Err()
+}
+
+// flowDepth returns the number of flow collections opened and not closed by a
+// value. Only values that start a flow collection are counted, since brackets
+// may appear in plain text.
+func flowDepth(line string) int {
+	if !strings.HasPrefix(line, "{") && !strings.HasPrefix(line, "[") {
+		return 0
+	}
+
+	return bracketDepth(line)
+}
+
+// bracketDepth returns the number of brackets opened and not closed in a
+// line, ignoring quoted text and comments.
+func bracketDepth(line string) int {
+	depth := 0
+	var quote rune
+	for i, r := range line {
+		switch {
+		case quote != 0:
+			if r == quote {
+				quote = 0
+			}
+		case r == '"' || r == '\'':
+			quote = r
+		case r == '#' && (i == 0 || line[i-1] == ' '):
+			return depth
+		case r == '{' || r == '[':
+			depth++
+		case r == '}' || r == ']':
+			depth--
+		}
+	}
+
+	return depth
+}
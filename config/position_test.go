@@ -0,0 +1,135 @@
+package config
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+var locateText = []byte(`---
+# A comment
+options:
+  foo:
+    default: bar
+
+tasks:
+  "one":
+    options:
+      baz: {default: qux}
+    run:
+      - echo one
+      - when:
+          equal: {foo: bar}
+        command: |
+          not: a key
+          echo two
+  two:
+    run:
+    - task: one
+    - command:
+        - echo three
+`)
+
+var locatetests = []struct {
+	path   []string
+	line   int
+	column int
+}{
+	{[]string{"options"}, 3, 1},
+	{[]string{"options", "foo", "default"}, 5, 5},
+	{[]string{"tasks", "one"}, 8, 3},
+	{[]string{"tasks", "one", "options", "baz"}, 10, 7},
+	{[]string{"tasks", "one", "options", "baz", "default"}, 10, 7},
+	{[]string{"tasks", "one", "run", "0"}, 12, 7},
+	{[]string{"tasks", "one", "run", "1", "when", "equal", "foo"}, 14, 11},
+	{[]string{"tasks", "one", "run", "1", "command"}, 15, 9},
+	{[]string{"tasks", "one", "run", "1", "not"}, 13, 7},
+	{[]string{"tasks", "two"}, 18, 3},
+	{[]string{"tasks", "two", "run", "1", "command", "0"}, 22, 9},
+	{[]string{"tasks", "three"}, 7, 1},
+	{[]string{"fake"}, 0, 0},
+}
+
+func TestLocate(t *testing.T) {
+	for _, tt := range locatetests {
+		line, column := Locate(locateText, tt.path...)
+		if tt.line != line || tt.column != column {
+			t.Errorf(
+				"Locate(text, %v): expected %d:%d, actual %d:%d",
+				tt.path, tt.line, tt.column, line, column,
+			)
+		}
+	}
+}
+
+var flowText = []byte(`tasks:
+  one: {
+    run: echo one,
+    usage: "{not a collection"
+  }
+  two:
+    run: [
+      echo two,
+    ]
+  three:
+    run: echo [not a collection
+  four:
+    - {a: b}
+    - c: d
+`)
+
+var flowtests = []struct {
+	path   []string
+	line   int
+	column int
+}{
+	{[]string{"tasks", "one"}, 2, 3},
+	{[]string{"tasks", "one", "run"}, 2, 3},
+	{[]string{"tasks", "two", "run"}, 7, 5},
+	{[]string{"tasks", "three"}, 10, 3},
+	{[]string{"tasks", "three", "run"}, 11, 5},
+	{[]string{"tasks", "four", "0", "a"}, 13, 5},
+	{[]string{"tasks", "four", "1", "c"}, 14, 7},
+}
+
+func TestLocate_flow(t *testing.T) {
+	for _, tt := range flowtests {
+		line, column := Locate(flowText, tt.path...)
+		if tt.line != line || tt.column != column {
+			t.Errorf(
+				"Locate(text, %v): expected %d:%d, actual %d:%d",
+				tt.path, tt.line, tt.column, line, column,
+			)
+		}
+	}
+}
+
+func TestLocate_long_line(t *testing.T) {
+	text := []byte("tasks:\n  long:\n    run: echo " + strings.Repeat("x", 100000) + "\n  after:\n    run: echo\n")
+
+	if line, column := Locate(text, "tasks", "after", "run"); line != 5 || column != 5 {
+		t.Errorf("Locate(text, tasks.after.run): expected 5:5, actual %d:%d", line, column)
+	}
+}
+
+var pathattests = []struct {
+	line     int
+	expected []string
+}{
+	{1, nil},
+	{3, []string{"options"}},
+	{6, []string{"options", "foo", "default"}},
+	{10, []string{"tasks", "one", "options", "baz"}},
+	{13, []string{"tasks", "one", "run", "1", "when"}},
+	{17, []string{"tasks", "one", "run", "1", "command"}},
+	{20, []string{"tasks", "two", "run", "0", "task"}},
+	{22, []string{"tasks", "two", "run", "1", "command", "0"}},
+}
+
+func TestPathAt(t *testing.T) {
+	for _, tt := range pathattests {
+		if actual := PathAt(locateText, tt.line); !reflect.DeepEqual(tt.expected, actual) {
+			t.Errorf("PathAt(text, %d): expected %v, actual %v", tt.line, tt.expected, actual)
+		}
+	}
+}
@@ -1,28 +1,33 @@
 package run
 
 import (
+	"bytes"
+	"io"
 	"os"
 	"os/exec"
+	"strings"
+	"syscall"
 
+	"github.com/rliebz/tusk/config/shell"
 	"github.com/rliebz/tusk/ui"
 )
 
 const shellEnvVar = "SHELL"
 const defaultShell = "sh"
 
-// ExecCommand executes a shell command.
-func ExecCommand(command string) error {
+// ExecCommand executes a shell command on behalf of a task. If no interpreter
+// is set, the user's shell is used.
+func ExecCommand(taskName string, interpreter shell.Interpreter, command string) error {
 	ui.PrintCommand(command)
 
-	shell := getShell()
-	cmd := exec.Command(shell, "-c", command) // nolint: gas
+	cmd := withShell(interpreter).Command(command)
 	cmd.Stdin = os.Stdin
 	if ui.Verbosity > ui.VerbosityLevelSilent {
 		cmd.Stdout = os.Stdout
 		cmd.Stderr = os.Stderr
 	}
 
-	if err := cmd.Run(); err != nil {
+	if err := runWithPrefix(cmd, taskName); err != nil {
 		ui.PrintCommandError(err)
 		return err
 	}
@@ -30,6 +35,88 @@ func ExecCommand(command string) error {
 	return nil
 }
 
+// CaptureCommand executes a shell command on behalf of a task, returning the
+// trimmed stdout and the exit code. A non-zero exit code is not an error.
+func CaptureCommand(
+	taskName string, interpreter shell.Interpreter, command string,
+) (string, int, error) {
+	ui.PrintCommand(command)
+
+	cmd := withShell(interpreter).Command(command)
+	cmd.Stdin = os.Stdin
+	if ui.Verbosity > ui.VerbosityLevelSilent {
+		cmd.Stderr = os.Stderr
+	}
+
+	stdout := new(bytes.Buffer)
+	cmd.Stdout = stdout
+
+	if err := runWithPrefix(cmd, taskName); err != nil {
+		code, ok := exitStatus(err)
+		if !ok {
+			ui.PrintCommandError(err)
+			return "", 0, err
+		}
+
+		return strings.TrimSpace(stdout.String()), code, nil
+	}
+
+	return strings.TrimSpace(stdout.String()), 0, nil
+}
+
+// exitStatus returns the exit status of a command that ran but failed.
+func exitStatus(err error) (int, bool) {
+	exitErr, ok := err.(*exec.ExitError)
+	if !ok {
+		return 0, false
+	}
+
+	ws, ok := exitErr.Sys().(syscall.WaitStatus)
+	if !ok {
+		return 0, false
+	}
+
+	return ws.ExitStatus(), true
+}
+
+// runWithPrefix runs a command, prefixing any output sent to the terminal
+// when configured to do so.
+func runWithPrefix(cmd *exec.Cmd, taskName string) error {
+	if !ui.PrefixOutput {
+		return runCommand(cmd)
+	}
+
+	prefix := ui.TaskPrefix(taskName)
+
+	var writers []*ui.PrefixWriter
+	for _, w := range []*io.Writer{&cmd.Stdout, &cmd.Stderr} {
+		if *w == os.Stdout || *w == os.Stderr {
+			pw := ui.NewPrefixWriter(*w, prefix)
+			writers = append(writers, pw)
+			*w = pw
+		}
+	}
+
+	err := runCommand(cmd)
+
+	for _, pw := range writers {
+		if flushErr := pw.Flush(); err == nil {
+			err = flushErr
+		}
+	}
+
+	return err
+}
+
+// withShell returns the interpreter passed, or the user's shell if empty.
+func withShell(interpreter shell.Interpreter) shell.Interpreter {
+	if len(interpreter) > 0 {
+		return interpreter
+	}
+
+	return shell.Interpreter{getShell(), "-c"}
+}
+
 // getShell returns the value of the `SHELL` environment variable, or `sh`.
 func getShell() string {
 	if shell := os.Getenv(shellEnvVar); shell != "" {
@@ -19,7 +19,7 @@ func TestExecCommand(t *testing.T) {
 
 	stderrActualBuf := new(bytes.Buffer)
 	ui.LoggerStderr.SetOutput(stderrActualBuf)
-	if err := ExecCommand(command); err != nil {
+	if err := ExecCommand("", nil, command); err != nil {
 		t.Fatalf(`execCommand("%s"): unexpected err: %s`, command, err)
 	}
 	stderrActual := stderrActualBuf.String()
@@ -46,7 +46,7 @@ func TestExecCommand_error(t *testing.T) {
 
 	bufActual := new(bytes.Buffer)
 	ui.LoggerStderr.SetOutput(bufActual)
-	if err := ExecCommand(command); err.Error() != errExpected.Error() {
+	if err := ExecCommand("", nil, command); err.Error() != errExpected.Error() {
 		t.Fatalf(`execCommand("%s"): expected error "%s", actual "%s"`,
 			command, errExpected, err,
 		)
@@ -86,3 +86,22 @@ func TestGetShell(t *testing.T) {
 		t.Errorf("getShell(): expected %v, actual %v", defaultShell, actual)
 	}
 }
+
+func TestCaptureCommand(t *testing.T) {
+	ui.LoggerStderr.SetOutput(new(bytes.Buffer))
+	defer ui.LoggerStderr.SetOutput(os.Stderr)
+
+	command := "echo '  hello  '; exit 2"
+	out, code, err := CaptureCommand("", nil, command)
+	if err != nil {
+		t.Fatalf(`CaptureCommand("%s"): unexpected err: %s`, command, err)
+	}
+
+	if out != "hello" {
+		t.Errorf(`CaptureCommand("%s"): expected output "hello", actual "%s"`, command, out)
+	}
+
+	if code != 2 {
+		t.Errorf(`CaptureCommand("%s"): expected exit code 2, actual %d`, command, code)
+	}
+}
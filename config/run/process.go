@@ -0,0 +1,117 @@
+package run
+
+import (
+	"errors"
+	"os/exec"
+	"sync"
+	"time"
+)
+
+// ErrKilled is returned for commands that were not started because every
+// running command was killed.
+var ErrKilled = errors.New("command was killed")
+
+// ProcessGroups starts each command in its own process group, so that every
+// process a command starts can be killed along with it. This is only set when
+// commands may be killed, since a command outside of the foreground process
+// group cannot read from the terminal.
+var ProcessGroups bool
+
+// KillTimeout is how long commands have to exit after being terminated before
+// they are killed.
+var KillTimeout = 2 * time.Second
+
+// running is the set of commands that have started and not yet finished,
+// along with whether each one has its own process group.
+var running = struct {
+	sync.Mutex
+	cmds   map[*exec.Cmd]bool
+	killed bool
+}{cmds: make(map[*exec.Cmd]bool)}
+
+// KillAll stops every command that is still running, along with any process
+// it started, and stops any more commands from starting until Resume is
+// called. Commands are terminated first, then killed if they have not exited
+// before the timeout.
+func KillAll() {
+	running.Lock()
+	running.killed = true
+	cmds := make(map[*exec.Cmd]bool, len(running.cmds))
+	for cmd, group := range running.cmds {
+		cmds[cmd] = group
+	}
+	running.Unlock()
+
+	for cmd, group := range cmds {
+		terminate(cmd, group)
+	}
+
+	// Processes started by a command may outlive it, so process groups are
+	// always killed once the timeout has passed
+	deadline := time.Now().Add(KillTimeout)
+	for time.Now().Before(deadline) && isRunning(cmds) {
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	for cmd, group := range cmds {
+		kill(cmd, group)
+	}
+}
+
+// isRunning tells if any of the commands passed are still running.
+func isRunning(cmds map[*exec.Cmd]bool) bool {
+	running.Lock()
+	defer running.Unlock()
+
+	for cmd := range cmds {
+		if _, ok := running.cmds[cmd]; ok {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Resume allows commands to start again after KillAll.
+func Resume() {
+	running.Lock()
+	defer running.Unlock()
+
+	running.killed = false
+}
+
+// runCommand runs a command so that it can be killed while it is running.
+func runCommand(cmd *exec.Cmd) error {
+	if err := startCommand(cmd); err != nil {
+		return err
+	}
+
+	err := cmd.Wait()
+
+	running.Lock()
+	delete(running.cmds, cmd)
+	running.Unlock()
+
+	return err
+}
+
+func startCommand(cmd *exec.Cmd) error {
+	running.Lock()
+	defer running.Unlock()
+
+	if running.killed {
+		return ErrKilled
+	}
+
+	group := ProcessGroups
+	if group {
+		setProcessGroup(cmd)
+	}
+
+	if err := cmd.Start(); err != nil {
+		return err
+	}
+
+	running.cmds[cmd] = group
+	return nil
+}
@@ -0,0 +1,57 @@
+package run
+
+import (
+	"bytes"
+	"os/exec"
+	"testing"
+	"time"
+)
+
+func TestKillAll(t *testing.T) {
+	defer Resume()
+	defer func(groups bool) { ProcessGroups = groups }(ProcessGroups)
+	ProcessGroups = true
+
+	// The shell does not exec the sleep, and waiting on the output pipe only
+	// finishes once the sleep has exited as well
+	cmd := exec.Command("sh", "-c", "sleep 10; :")
+	cmd.Stdout = new(bytes.Buffer)
+
+	done := make(chan error, 1)
+	go func() { done <- runCommand(cmd) }()
+
+	// Wait for the command to start before killing it
+	for i := 0; ; i++ {
+		running.Lock()
+		started := len(running.cmds) > 0
+		running.Unlock()
+		if started {
+			break
+		}
+		if i > 100 {
+			t.Fatal("runCommand(): command did not start")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	KillAll()
+
+	select {
+	case err := <-done:
+		if err == nil {
+			t.Error("runCommand(): expected err for killed command, actual nil")
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("KillAll(): command was not killed")
+	}
+
+	if err := runCommand(exec.Command("true")); err != ErrKilled {
+		t.Errorf("runCommand() after KillAll(): expected %v, actual %v", ErrKilled, err)
+	}
+
+	Resume()
+
+	if err := runCommand(exec.Command("true")); err != nil {
+		t.Errorf("runCommand() after Resume(): unexpected err: %s", err)
+	}
+}
@@ -0,0 +1,34 @@
+//go:build !windows
+// +build !windows
+
+package run
+
+import (
+	"os/exec"
+	"syscall"
+)
+
+func setProcessGroup(cmd *exec.Cmd) {
+	if cmd.SysProcAttr == nil {
+		cmd.SysProcAttr = new(syscall.SysProcAttr)
+	}
+	cmd.SysProcAttr.Setpgid = true
+}
+
+func terminate(cmd *exec.Cmd, group bool) {
+	signal(cmd, group, syscall.SIGTERM)
+}
+
+func kill(cmd *exec.Cmd, group bool) {
+	signal(cmd, group, syscall.SIGKILL)
+}
+
+// signal sends a signal to a command, or to its whole process group.
+func signal(cmd *exec.Cmd, group bool, sig syscall.Signal) {
+	if group {
+		syscall.Kill(-cmd.Process.Pid, sig) // nolint: errcheck, gas
+		return
+	}
+
+	cmd.Process.Signal(sig) // nolint: errcheck, gas
+}
@@ -0,0 +1,15 @@
+package run
+
+import "os/exec"
+
+// Windows has no process groups that can be signaled, so commands are only
+// ever killed directly.
+func setProcessGroup(cmd *exec.Cmd) {}
+
+func terminate(cmd *exec.Cmd, group bool) {
+	cmd.Process.Kill() // nolint: errcheck, gas
+}
+
+func kill(cmd *exec.Cmd, group bool) {
+	cmd.Process.Kill() // nolint: errcheck, gas
+}
@@ -1,17 +1,52 @@
 package run
 
 import (
+	"errors"
 	"fmt"
+	"regexp"
 
 	"github.com/rliebz/tusk/config/marshal"
+	"github.com/rliebz/tusk/config/shell"
 	"github.com/rliebz/tusk/config/when"
 )
 
 // Run defines a a single runnable script within a task.
 type Run struct {
-	When    *when.When         `yaml:",omitempty"`
-	Command marshal.StringList `yaml:",omitempty"`
-	Task    marshal.StringList `yaml:",omitempty"`
+	When     *when.When         `yaml:",omitempty"`
+	Command  marshal.StringList `yaml:",omitempty"`
+	Task     marshal.StringList `yaml:",omitempty"`
+	Script   string             `yaml:",omitempty"`
+	Register string             `yaml:",omitempty"`
+	Template bool               `yaml:",omitempty"`
+
+	Interpreter shell.Interpreter `yaml:",omitempty"`
+}
+
+// templateOptionPattern matches options referenced in a command template.
+var templateOptionPattern = regexp.MustCompile(
+	`\.Options\.(\w+)|index\s+\.Options\s+"([\w-]+)"`,
+)
+
+// Dependencies returns a list of options that are required explicitly.
+// This does not include interpolations.
+func (r *Run) Dependencies() []string {
+	options := r.When.Dependencies()
+
+	if !r.Template {
+		return options
+	}
+
+	for _, command := range r.Command {
+		for _, groups := range templateOptionPattern.FindAllStringSubmatch(command, -1) {
+			for _, name := range groups[1:] {
+				if name != "" {
+					options = append(options, name)
+				}
+			}
+		}
+	}
+
+	return options
 }
 
 // UnmarshalYAML allows plain strings to represent a run struct. The value of
@@ -37,6 +72,25 @@ func (r *Run) UnmarshalYAML(unmarshal func(interface{}) error) error {
 				)
 			}
 
+			if runItem.Script != "" && (len(runItem.Command) != 0 || len(runItem.Task) != 0) {
+				return errors.New("script cannot be combined with a command or subtask")
+			}
+
+			if len(runItem.Interpreter) != 0 && len(runItem.Command) == 0 && runItem.Script == "" {
+				return errors.New("interpreter is only supported for commands and scripts")
+			}
+
+			if runItem.Template && len(runItem.Command) == 0 {
+				return errors.New("template is only supported for commands")
+			}
+
+			if runItem.Register != "" && len(runItem.Command) != 1 {
+				return fmt.Errorf(
+					"register (%s) requires exactly one command",
+					runItem.Register,
+				)
+			}
+
 			return nil
 		},
 	}
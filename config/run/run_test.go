@@ -1,8 +1,13 @@
 package run
 
-import "testing"
-import "gopkg.in/yaml.v2"
-import "reflect"
+import (
+	"reflect"
+	"testing"
+
+	"github.com/rliebz/tusk/config/marshal"
+	"github.com/rliebz/tusk/config/when"
+	"gopkg.in/yaml.v2"
+)
 
 func TestRun_UnmarshalYAML(t *testing.T) {
 	s1 := []byte(`command: example`)
@@ -52,6 +57,79 @@ func TestRun_UnmarshalYAML_command_and_subtask(t *testing.T) {
 	}
 }
 
+func TestRun_UnmarshalYAML_register(t *testing.T) {
+	valid := []byte(`{command: echo hello, register: greeting}`)
+	r := Run{}
+	if err := yaml.Unmarshal(valid, &r); err != nil {
+		t.Fatalf("yaml.Unmarshal(%s, ...): unexpected error: %s", valid, err)
+	}
+
+	if r.Register != "greeting" {
+		t.Errorf(
+			"yaml.Unmarshal(%s, ...): expected register `%s`, actual `%s`",
+			valid, "greeting", r.Register,
+		)
+	}
+
+	for _, s := range [][]byte{
+		[]byte(`{command: [echo one, echo two], register: greeting}`),
+		[]byte(`{task: mytask, register: greeting}`),
+	} {
+		if err := yaml.Unmarshal(s, &Run{}); err == nil {
+			t.Errorf("yaml.Unmarshal(%s, ...): expected error, received nil", s)
+		}
+	}
+}
+
+func TestRun_UnmarshalYAML_script(t *testing.T) {
+	valid := []byte("script: |\n  echo one\n  echo two\n")
+	r := Run{}
+	if err := yaml.Unmarshal(valid, &r); err != nil {
+		t.Fatalf("yaml.Unmarshal(%s, ...): unexpected error: %s", valid, err)
+	}
+
+	if expected := "echo one\necho two\n"; r.Script != expected {
+		t.Errorf(
+			"yaml.Unmarshal(%s, ...): expected script `%s`, actual `%s`",
+			valid, expected, r.Script,
+		)
+	}
+
+	for _, s := range [][]byte{
+		[]byte(`{script: echo one, command: echo two}`),
+		[]byte(`{script: echo one, task: mytask}`),
+		[]byte(`{script: echo one, register: greeting}`),
+		[]byte(`{task: mytask, interpreter: bash -c}`),
+	} {
+		if err := yaml.Unmarshal(s, &Run{}); err == nil {
+			t.Errorf("yaml.Unmarshal(%s, ...): expected error, received nil", s)
+		}
+	}
+}
+
+func TestRun_Dependencies(t *testing.T) {
+	r := Run{
+		When: &when.When{
+			Equal: map[string]marshal.StringList{"foo": {"true"}},
+		},
+		Command: marshal.StringList{
+			`echo {{.Options.bar}} {{index .Options "baz-qux"}} ${ignored}`,
+		},
+		Template: true,
+	}
+
+	expected := []string{"foo", "bar", "baz-qux"}
+	if actual := r.Dependencies(); !reflect.DeepEqual(expected, actual) {
+		t.Errorf("Run.Dependencies(): expected %v, actual %v", expected, actual)
+	}
+
+	r.Template = false
+	expected = []string{"foo"}
+	if actual := r.Dependencies(); !reflect.DeepEqual(expected, actual) {
+		t.Errorf("Run.Dependencies(): expected %v, actual %v", expected, actual)
+	}
+}
+
 type runListHolder struct {
 	Foo List
 }
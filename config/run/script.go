@@ -0,0 +1,169 @@
+package run
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"runtime"
+	"strconv"
+	"strings"
+
+	"github.com/rliebz/tusk/config/shell"
+	"github.com/rliebz/tusk/ui"
+)
+
+// errTrap writes the line number of each failing statement to file descriptor
+// 3. Errtrace is set so that failures inside functions are also recorded.
+const errTrap = `set -E; trap 'echo "$LINENO" >&3' ERR` + "\n"
+
+// strictFlags are added when running scripts with a known shell, so that a
+// failing statement stops the script.
+var strictFlags = map[string][]string{
+	"bash": {"-euo", "pipefail"},
+	"ksh":  {"-euo", "pipefail"},
+	"zsh":  {"-euo", "pipefail"},
+	"ash":  {"-eu"},
+	"dash": {"-eu"},
+	"sh":   {"-eu"},
+}
+
+// defaultShells are used to run scripts when no interpreter is set. The first
+// one found is used.
+var defaultShells = []string{"bash", "sh"}
+
+// ExecScript writes a script to a temporary file and executes it on behalf of
+// a task. Scripts run by a known shell, or by the default shell if no
+// interpreter is set, have strict error handling. When the script is run by
+// bash, errors include the line number of the statement that failed.
+func ExecScript(taskName string, interpreter shell.Interpreter, script string) error {
+	ui.PrintCommand(script)
+
+	line, err := runScript(taskName, withStrictShell(interpreter), script)
+	if err != nil {
+		if line > 0 {
+			ui.PrintCommandError(fmt.Errorf("line %d: %s", line, err))
+		} else {
+			ui.PrintCommandError(err)
+		}
+		return err
+	}
+
+	return nil
+}
+
+// runScript runs a script, returning the line number of the last statement
+// that failed, or 0 if unknown.
+func runScript(taskName string, interpreter shell.Interpreter, script string) (int, error) {
+	traced := supportsErrTrap(interpreter)
+	if traced {
+		script = errTrap + script
+	}
+
+	path, err := writeTempFile("tusk-script-", script)
+	if err != nil {
+		return 0, err
+	}
+	defer os.Remove(path) // nolint: errcheck
+
+	cmd := interpreter.Script(path)
+	cmd.Stdin = os.Stdin
+	if ui.Verbosity > ui.VerbosityLevelSilent {
+		cmd.Stdout = os.Stdout
+		cmd.Stderr = os.Stderr
+	}
+
+	if !traced {
+		return 0, runWithPrefix(cmd, taskName)
+	}
+
+	trace, err := ioutil.TempFile("", "tusk-trace-")
+	if err != nil {
+		return 0, err
+	}
+	defer os.Remove(trace.Name()) // nolint: errcheck
+	defer trace.Close()           // nolint: errcheck
+
+	cmd.ExtraFiles = []*os.File{trace}
+	if err := runWithPrefix(cmd, taskName); err != nil {
+		if line := lastLine(trace.Name()); line > 0 {
+			return line - strings.Count(errTrap, "\n"), err
+		}
+		return 0, err
+	}
+
+	return 0, nil
+}
+
+// withStrictShell returns the interpreter passed, or a shell if empty. Strict
+// flags are added for known shells.
+func withStrictShell(interpreter shell.Interpreter) shell.Interpreter {
+	if len(interpreter) == 0 {
+		interpreter = shell.Interpreter{defaultShells[len(defaultShells)-1]}
+		for _, name := range defaultShells {
+			if _, err := exec.LookPath(name); err == nil {
+				interpreter = shell.Interpreter{name}
+				break
+			}
+		}
+	}
+
+	flags, ok := strictFlags[interpreter.Program()]
+	if !ok {
+		return interpreter
+	}
+
+	strict := append(shell.Interpreter{interpreter[0]}, flags...)
+	return append(strict, interpreter[1:]...)
+}
+
+// supportsErrTrap tells if the failing line can be found for an interpreter.
+func supportsErrTrap(interpreter shell.Interpreter) bool {
+	if runtime.GOOS == "windows" || len(interpreter) == 0 {
+		return false
+	}
+
+	return interpreter.Program() == "bash"
+}
+
+// writeTempFile writes content to a new temporary file, returning its path.
+func writeTempFile(prefix, content string) (string, error) {
+	f, err := ioutil.TempFile("", prefix)
+	if err != nil {
+		return "", err
+	}
+
+	if _, err := f.WriteString(content); err != nil {
+		f.Close()           // nolint: errcheck, gas
+		os.Remove(f.Name()) // nolint: errcheck, gas
+		return "", err
+	}
+
+	if err := f.Close(); err != nil {
+		os.Remove(f.Name()) // nolint: errcheck, gas
+		return "", err
+	}
+
+	return f.Name(), nil
+}
+
+// lastLine returns the last line number written to a trace file, or 0.
+func lastLine(path string) int {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return 0
+	}
+
+	fields := bytes.Fields(data)
+	if len(fields) == 0 {
+		return 0
+	}
+
+	line, err := strconv.Atoi(string(fields[len(fields)-1]))
+	if err != nil {
+		return 0
+	}
+
+	return line
+}
@@ -0,0 +1,105 @@
+package run
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"reflect"
+	"testing"
+
+	"github.com/rliebz/tusk/config/shell"
+	"github.com/rliebz/tusk/ui"
+)
+
+func TestExecScript(t *testing.T) {
+	ui.LoggerStderr.SetOutput(new(bytes.Buffer))
+	defer ui.LoggerStderr.SetOutput(os.Stderr)
+
+	script := "true\necho hello > /dev/null\n"
+	if err := ExecScript("", shell.Interpreter{"sh", "-eu"}, script); err != nil {
+		t.Errorf("ExecScript(%q): unexpected err: %s", script, err)
+	}
+}
+
+var strictshelltests = []struct {
+	interpreter shell.Interpreter
+	expected    shell.Interpreter
+}{
+	{shell.Interpreter{"bash"}, shell.Interpreter{"bash", "-euo", "pipefail"}},
+	{shell.Interpreter{"/bin/bash", "-c"}, shell.Interpreter{"/bin/bash", "-euo", "pipefail", "-c"}},
+	{shell.Interpreter{"sh", "-x"}, shell.Interpreter{"sh", "-eu", "-x"}},
+	{shell.Interpreter{"node", "-e"}, shell.Interpreter{"node", "-e"}},
+}
+
+func TestWithStrictShell(t *testing.T) {
+	for _, tt := range strictshelltests {
+		if actual := withStrictShell(tt.interpreter); !reflect.DeepEqual(tt.expected, actual) {
+			t.Errorf("withStrictShell(%v): expected %v, actual %v", tt.interpreter, tt.expected, actual)
+		}
+	}
+
+	actual := withStrictShell(nil)
+	if _, err := exec.LookPath("bash"); err == nil {
+		expected := shell.Interpreter{"bash", "-euo", "pipefail"}
+		if !reflect.DeepEqual(expected, actual) {
+			t.Errorf("withStrictShell(nil): expected %v, actual %v", expected, actual)
+		}
+	}
+}
+
+var scripterrortests = []struct {
+	interpreter shell.Interpreter
+	script      string
+	err         string
+	line        int
+}{
+	{shell.Interpreter{"sh", "-eu"}, "true\nfalse\ntrue\n", "exit status 1", 0},
+	{shell.Interpreter{"bash", "-eu"}, "true\nfalse\ntrue\n", "exit status 1", 2},
+	{
+		shell.Interpreter{"bash", "-euo", "pipefail"},
+		"true\necho | false | cat\ntrue\n", "exit status 1", 2,
+	},
+	{shell.Interpreter{"bash", "-eu"}, "f() {\n  false\n}\nf\n", "exit status 1", 2},
+	{shell.Interpreter{"bash", "-eu"}, "false || true\nexit 3\n", "exit status 3", 0},
+}
+
+func TestExecScript_error(t *testing.T) {
+	if _, err := exec.LookPath("bash"); err != nil {
+		t.Skip("bash is not installed")
+	}
+
+	for _, tt := range scripterrortests {
+		errExpected := errors.New(tt.err)
+
+		bufExpected := new(bytes.Buffer)
+		ui.LoggerStderr.SetOutput(bufExpected)
+		ui.PrintCommand(tt.script)
+		if tt.line > 0 {
+			ui.PrintCommandError(fmt.Errorf("line %d: %s", tt.line, errExpected))
+		} else {
+			ui.PrintCommandError(errExpected)
+		}
+		expected := bufExpected.String()
+
+		bufActual := new(bytes.Buffer)
+		ui.LoggerStderr.SetOutput(bufActual)
+		err := ExecScript("", tt.interpreter, tt.script)
+		actual := bufActual.String()
+
+		if err == nil || err.Error() != errExpected.Error() {
+			t.Errorf("ExecScript(%q): expected error %q, actual %v", tt.script, errExpected, err)
+			continue
+		}
+
+		if expected != actual {
+			t.Errorf(
+				"ExecScript(%q):\nexpected output:\n`%s`\nactual output:\n`%s`",
+				tt.script, expected, actual,
+			)
+		}
+	}
+
+	ui.LoggerStderr.SetOutput(os.Stderr)
+}
@@ -0,0 +1,146 @@
+package config
+
+import (
+	"reflect"
+	"strings"
+
+	"github.com/rliebz/tusk/config/option"
+	"github.com/rliebz/tusk/config/run"
+	yaml "gopkg.in/yaml.v2"
+)
+
+const schemaVersion = "http://json-schema.org/draft-07/schema#"
+
+var (
+	unmarshalerType = reflect.TypeOf((*yaml.Unmarshaler)(nil)).Elem()
+
+	// shorthandTypes are structs that may also be written as a single scalar.
+	shorthandTypes = map[reflect.Type]bool{
+		reflect.TypeOf(run.Run{}):                                 true,
+		defaultValuesType(reflect.TypeOf(option.Option{})).Elem(): true,
+	}
+)
+
+// Schema returns a JSON Schema describing the config file format. The schema
+// is generated from the types used to parse the config file, so it is always
+// in sync with what the parser accepts.
+func Schema() map[string]interface{} {
+	g := schemaGenerator{definitions: make(map[string]interface{})}
+
+	root := g.structSchema(reflect.TypeOf(Config{}))
+	root["$schema"] = schemaVersion
+	root["title"] = "tusk"
+	root["definitions"] = g.definitions
+
+	return root
+}
+
+type schemaGenerator struct {
+	definitions map[string]interface{}
+}
+
+// schema returns the schema for any type found in the config file.
+func (g *schemaGenerator) schema(t reflect.Type) map[string]interface{} {
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	switch t.Kind() {
+	case reflect.Struct:
+		name := strings.ToLower(t.Name())
+		if _, ok := g.definitions[name]; !ok {
+			// Reserve the name first in case the type refers to itself
+			g.definitions[name] = nil
+			g.definitions[name] = g.structSchema(t)
+		}
+
+		ref := map[string]interface{}{"$ref": "#/definitions/" + name}
+		if shorthandTypes[t] {
+			return oneOf(g.scalar(), ref)
+		}
+		return ref
+	case reflect.Slice:
+		items := g.schema(t.Elem())
+		array := map[string]interface{}{"type": "array", "items": items}
+
+		// Lists with custom unmarshalling also accept a single item
+		if reflect.PtrTo(t).Implements(unmarshalerType) {
+			return oneOf(items, array)
+		}
+		return array
+	case reflect.Map:
+		return map[string]interface{}{
+			"type":                 "object",
+			"additionalProperties": g.schema(t.Elem()),
+		}
+	case reflect.Bool:
+		return map[string]interface{}{"type": "boolean"}
+	default:
+		return g.scalar()
+	}
+}
+
+// structSchema returns the schema for the yaml fields of a struct.
+func (g *schemaGenerator) structSchema(t reflect.Type) map[string]interface{} {
+	properties := make(map[string]interface{})
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if field.PkgPath != "" {
+			continue
+		}
+
+		name := yamlName(field)
+		if name == "-" {
+			continue
+		}
+
+		properties[name] = g.schema(field.Type)
+	}
+
+	return map[string]interface{}{
+		"type":                 "object",
+		"properties":           properties,
+		"additionalProperties": false,
+	}
+}
+
+// yamlName returns the key used for a struct field in yaml.
+func yamlName(field reflect.StructField) string {
+	tag := strings.Split(field.Tag.Get("yaml"), ",")[0]
+	if tag != "" {
+		return tag
+	}
+
+	return strings.ToLower(field.Name)
+}
+
+// scalar returns a schema matching any yaml scalar, which can all be parsed
+// as strings.
+func (g *schemaGenerator) scalar() map[string]interface{} {
+	g.definitions["scalar"] = map[string]interface{}{
+		"type": []string{"boolean", "number", "string"},
+	}
+
+	return map[string]interface{}{"$ref": "#/definitions/scalar"}
+}
+
+// oneOf returns a schema matching exactly one of the schemas passed. Nested
+// oneOf schemas are flattened.
+func oneOf(schemas ...map[string]interface{}) map[string]interface{} {
+	var flattened []map[string]interface{}
+	for _, schema := range schemas {
+		if nested, ok := schema["oneOf"].([]map[string]interface{}); ok {
+			flattened = append(flattened, nested...)
+			continue
+		}
+
+		flattened = append(flattened, schema)
+	}
+
+	return map[string]interface{}{"oneOf": flattened}
+}
+
+func defaultValuesType(t reflect.Type) reflect.Type {
+	field, _ := t.FieldByName("DefaultValues")
+	return field.Type
+}
@@ -0,0 +1,89 @@
+package config
+
+import (
+	"encoding/json"
+	"reflect"
+	"sort"
+	"testing"
+)
+
+var schemapropertiestests = []struct {
+	definition string
+	expected   []string
+}{
+	{"complete", []string{"command", "directories"}},
+	{"option", []string{
+		"complete", "default", "environment", "export", "private", "required", "short",
+		"type", "usage", "values",
+	}},
+	{"run", []string{
+		"command", "interpreter", "register", "script", "task", "template", "when",
+	}},
+	{"task", []string{
+		"aliases", "category", "description", "interpreter", "options", "private", "run",
+		"usage", "watch",
+	}},
+	{"value", []string{"command", "value", "when"}},
+	{"when", []string{"command", "equal", "exists", "not_equal", "os"}},
+}
+
+func TestSchema_properties(t *testing.T) {
+	schema := Schema()
+
+	definitions, ok := schema["definitions"].(map[string]interface{})
+	if !ok {
+		t.Fatalf("Schema(): definitions is not a map: %#v", schema["definitions"])
+	}
+
+	for _, tt := range schemapropertiestests {
+		definition, ok := definitions[tt.definition].(map[string]interface{})
+		if !ok {
+			t.Errorf("Schema(): definition %s not found", tt.definition)
+			continue
+		}
+
+		properties, _ := definition["properties"].(map[string]interface{})
+		var actual []string
+		for name := range properties {
+			actual = append(actual, name)
+		}
+		sort.Strings(actual)
+
+		if !reflect.DeepEqual(tt.expected, actual) {
+			t.Errorf(
+				"Schema(): properties for %s:\nexpected: %v\nactual: %v",
+				tt.definition, tt.expected, actual,
+			)
+		}
+	}
+}
+
+func TestSchema_shorthand(t *testing.T) {
+	text, err := json.Marshal(Schema())
+	if err != nil {
+		t.Fatalf("json.Marshal(Schema()): unexpected error: %s", err)
+	}
+
+	var schema struct {
+		Definitions struct {
+			Task struct {
+				Properties struct {
+					Run struct {
+						OneOf []map[string]interface{}
+					}
+				}
+			}
+		}
+	}
+	if err := json.Unmarshal(text, &schema); err != nil {
+		t.Fatalf("json.Unmarshal(): unexpected error: %s", err)
+	}
+
+	// A run list can be a single command, a single run item, or a list
+	if actual := len(schema.Definitions.Task.Properties.Run.OneOf); actual != 3 {
+		t.Errorf(
+			"Schema(): expected 3 forms for task run, got %d: %v",
+			actual, schema.Definitions.Task.Properties.Run.OneOf,
+		)
+	}
+}
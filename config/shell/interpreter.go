@@ -0,0 +1,80 @@
+package shell
+
+import (
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/rliebz/tusk/config/marshal"
+)
+
+// Interpreter is a program and its arguments used to run commands, such as
+// `bash -euo pipefail -c`. Each command is passed as the final argument.
+type Interpreter []string
+
+// Default is the interpreter used when none is configured.
+var Default = Interpreter{"sh", "-c"}
+
+// UnmarshalYAML allows an interpreter to be written as a single string, which
+// is split on whitespace, or as a list of arguments.
+func (i *Interpreter) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	var single string
+	singleCandidate := marshal.UnmarshalCandidate{
+		Unmarshal: func() error { return unmarshal(&single) },
+		Assign:    func() { *i = strings.Fields(single) },
+	}
+
+	var list []string
+	listCandidate := marshal.UnmarshalCandidate{
+		Unmarshal: func() error { return unmarshal(&list) },
+		Assign:    func() { *i = list },
+	}
+
+	return marshal.UnmarshalOneOf(singleCandidate, listCandidate)
+}
+
+// Command returns a command to be run by the interpreter, or by the default
+// interpreter if none is set.
+func (i Interpreter) Command(command string) *exec.Cmd {
+	if len(i) == 0 {
+		i = Default
+	}
+
+	args := append(append([]string{}, i[1:]...), command)
+	return exec.Command(i[0], args...) // nolint: gas
+}
+
+// inlineFlags are the flags used by common interpreters to pass code as a
+// string rather than as a file, such as `sh -c`, `node -e`, or `deno eval`.
+var inlineFlags = map[string]bool{"-c": true, "-e": true, "--eval": true, "eval": true}
+
+// Script returns a command running a script file with the interpreter. A
+// trailing flag used to pass code as a string, such as `-c` or `-e`, is
+// dropped so the file path is read as a script instead.
+func (i Interpreter) Script(path string) *exec.Cmd {
+	if len(i) == 0 {
+		i = Default
+	}
+
+	args := append([]string{}, i[1:]...)
+	if len(args) > 0 && inlineFlags[args[len(args)-1]] {
+		args = args[:len(args)-1]
+	}
+
+	return exec.Command(i[0], append(args, path)...) // nolint: gas
+}
+
+// Program returns the name of the program run by the interpreter, without
+// its directory or any .exe extension.
+func (i Interpreter) Program() string {
+	if len(i) == 0 {
+		i = Default
+	}
+
+	return strings.TrimSuffix(filepath.Base(i[0]), ".exe")
+}
+
+// String returns the interpreter as it would be written in a shell.
+func (i Interpreter) String() string {
+	return strings.Join(i, " ")
+}
@@ -0,0 +1,98 @@
+package shell
+
+import (
+	"reflect"
+	"testing"
+
+	yaml "gopkg.in/yaml.v2"
+)
+
+var unmarshaltests = []struct {
+	input    string
+	expected Interpreter
+}{
+	{`bash -euo pipefail -c`, Interpreter{"bash", "-euo", "pipefail", "-c"}},
+	{`[python3, -c]`, Interpreter{"python3", "-c"}},
+	{`["/path with spaces/sh", -c]`, Interpreter{"/path with spaces/sh", "-c"}},
+	{`""`, Interpreter{}},
+}
+
+func TestInterpreter_UnmarshalYAML(t *testing.T) {
+	for _, tt := range unmarshaltests {
+		var actual Interpreter
+		if err := yaml.Unmarshal([]byte(tt.input), &actual); err != nil {
+			t.Errorf("yaml.Unmarshal(%s): unexpected error: %s", tt.input, err)
+			continue
+		}
+
+		if !reflect.DeepEqual(tt.expected, actual) {
+			t.Errorf("yaml.Unmarshal(%s): expected %#v, actual %#v", tt.input, tt.expected, actual)
+		}
+	}
+}
+
+var commandtests = []struct {
+	interpreter Interpreter
+	expected    []string
+}{
+	{nil, []string{"sh", "-c", "echo hi"}},
+	{Interpreter{"bash", "-eu", "-c"}, []string{"bash", "-eu", "-c", "echo hi"}},
+}
+
+func TestInterpreter_Command(t *testing.T) {
+	for _, tt := range commandtests {
+		cmd := tt.interpreter.Command("echo hi")
+		if !reflect.DeepEqual(tt.expected, cmd.Args) {
+			t.Errorf("%v.Command(): expected args %v, actual %v", tt.interpreter, tt.expected, cmd.Args)
+		}
+	}
+}
+
+func TestInterpreter_Command_output(t *testing.T) {
+	out, err := Interpreter{"sh", "-c"}.Command("printf '%s' hello").Output()
+	if err != nil {
+		t.Fatalf("Command(): unexpected error: %s", err)
+	}
+
+	if expected := "hello"; expected != string(out) {
+		t.Errorf("Command(): expected output %q, actual %q", expected, out)
+	}
+}
+
+var scripttests = []struct {
+	interpreter Interpreter
+	expected    []string
+}{
+	{nil, []string{"sh", "script.sh"}},
+	{Interpreter{"bash", "-eu", "-c"}, []string{"bash", "-eu", "script.sh"}},
+	{Interpreter{"python3"}, []string{"python3", "script.sh"}},
+	{Interpreter{"python3", "-c"}, []string{"python3", "script.sh"}},
+	{Interpreter{"node", "-e"}, []string{"node", "script.sh"}},
+	{Interpreter{"deno", "eval"}, []string{"deno", "script.sh"}},
+}
+
+var programtests = []struct {
+	interpreter Interpreter
+	expected    string
+}{
+	{nil, "sh"},
+	{Interpreter{"/usr/local/bin/bash", "-c"}, "bash"},
+	{Interpreter{"node.exe", "-e"}, "node"},
+}
+
+func TestInterpreter_Program(t *testing.T) {
+	for _, tt := range programtests {
+		if actual := tt.interpreter.Program(); tt.expected != actual {
+			t.Errorf("%v.Program(): expected %q, actual %q", tt.interpreter, tt.expected, actual)
+		}
+	}
+}
+
+func TestInterpreter_Script(t *testing.T) {
+	for _, tt := range scripttests {
+		cmd := tt.interpreter.Script("script.sh")
+		if !reflect.DeepEqual(tt.expected, cmd.Args) {
+			t.Errorf("%v.Script(): expected args %v, actual %v", tt.interpreter, tt.expected, cmd.Args)
+		}
+	}
+}
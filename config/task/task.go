@@ -1,23 +1,48 @@
 package task
 
 import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+
+	"github.com/pkg/errors"
+	"github.com/rliebz/tusk/config/marshal"
 	"github.com/rliebz/tusk/config/option"
 	"github.com/rliebz/tusk/config/run"
+	"github.com/rliebz/tusk/config/shell"
 	"github.com/rliebz/tusk/config/when"
+	"github.com/rliebz/tusk/interp"
 	"github.com/rliebz/tusk/ui"
+	yaml "gopkg.in/yaml.v2"
 )
 
+// ExitCodeSuffix is appended to a registered name for the exit code variable.
+const ExitCodeSuffix = "-exit-code"
+
 // Task is a single task to be run by CLI.
 type Task struct {
 	Options     map[string]*option.Option `yaml:",omitempty"`
 	Run         run.List
-	Usage       string `yaml:",omitempty"`
-	Description string `yaml:",omitempty"`
+	Usage       string             `yaml:",omitempty"`
+	Description string             `yaml:",omitempty"`
+	Aliases     marshal.StringList `yaml:",omitempty"`
+	Category    string             `yaml:",omitempty"`
+	Private     bool               `yaml:",omitempty"`
+	Interpreter shell.Interpreter  `yaml:",omitempty"`
+	Watch       marshal.StringList `yaml:",omitempty"`
 
 	// Computed members not specified in yaml file
-	Name     string  `yaml:"-"`
-	SubTasks []*Task `yaml:"-"`
-	Vars     map[string]string
+	Name     string            `yaml:"-"`
+	Dir      string            `yaml:"-"`
+	User     bool              `yaml:"-"`
+	SubTasks []*Task           `yaml:"-"`
+	Vars     map[string]string `yaml:"-"`
+
+	// Strict makes references to undefined options errors, not warnings
+	Strict bool `yaml:"-"`
+
+	registered map[string]string
 }
 
 // UnmarshalYAML unmarshals and assigns names to options.
@@ -35,6 +60,21 @@ func (t *Task) UnmarshalYAML(unmarshal func(interface{}) error) error {
 	return nil
 }
 
+// HasName tells if a name matches the task's name or any of its aliases.
+func (t *Task) HasName(name string) bool {
+	if t.Name == name {
+		return true
+	}
+
+	for _, alias := range t.Aliases {
+		if alias == name {
+			return true
+		}
+	}
+
+	return false
+}
+
 // Dependencies returns a list of options that are required explicitly.
 // This does not include interpolations.
 func (t *Task) Dependencies() []string {
@@ -44,26 +84,110 @@ func (t *Task) Dependencies() []string {
 		options = append(options, opt.Dependencies()...)
 	}
 	for _, run := range t.Run {
-		options = append(options, run.When.Dependencies()...)
+		options = append(options, run.Dependencies()...)
 	}
 
 	return options
 }
 
-// Execute runs the Run scripts in the task.
-func (t *Task) Execute() error {
-	for _, r := range t.Run {
-		if err := t.run(r); err != nil {
-			return err
+// WatchPatterns returns the absolute glob patterns of the files to watch for
+// changes, which are the patterns of the task and each of its sub-tasks.
+// Relative patterns are relative to the directory of the task defining them.
+func (t *Task) WatchPatterns() ([]string, error) {
+	var patterns []string
+	for _, pattern := range t.Watch {
+		pattern, err := interp.String(pattern, t.vars())
+		if err != nil {
+			return nil, err
 		}
+
+		if !filepath.IsAbs(pattern) {
+			if pattern, err = filepath.Abs(filepath.Join(t.Dir, pattern)); err != nil {
+				return nil, err
+			}
+		}
+
+		patterns = append(patterns, pattern)
 	}
 
-	return nil
+	for _, subTask := range t.SubTasks {
+		nested, err := subTask.WatchPatterns()
+		if err != nil {
+			return nil, err
+		}
+
+		for _, pattern := range nested {
+			if !containsString(patterns, pattern) {
+				patterns = append(patterns, pattern)
+			}
+		}
+	}
+
+	return patterns, nil
+}
+
+func containsString(items []string, item string) bool {
+	for _, i := range items {
+		if i == item {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Execute runs the Run scripts in the task from the task's directory, if set.
+func (t *Task) Execute() (err error) {
+	record := ui.StartTask(t.Name)
+	defer func() { record.Finish(err) }()
+
+	t.registered = make(map[string]string)
+
+	return inDir(t.Dir, func() error {
+		for _, r := range t.Run {
+			if err := t.run(r); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+// inDir calls a function from within a directory, then changes back to the
+// previous working directory. If no directory is passed, the working
+// directory is not changed.
+func inDir(dir string, f func() error) (err error) {
+	if dir == "" {
+		return f()
+	}
+
+	wd, err := os.Getwd()
+	if err != nil {
+		return err
+	}
+
+	if err := os.Chdir(dir); err != nil {
+		return err
+	}
+
+	defer func() {
+		if chdirErr := os.Chdir(wd); err == nil {
+			err = chdirErr
+		}
+	}()
+
+	return f()
 }
 
 // run executes a Run struct.
 func (t *Task) run(r *run.Run) error {
 
+	r, err := t.interpolate(r)
+	if err != nil {
+		return err
+	}
+
 	if ok, err := t.shouldRun(r); !ok || err != nil {
 		return err
 	}
@@ -72,6 +196,10 @@ func (t *Task) run(r *run.Run) error {
 		return err
 	}
 
+	if err := t.runScript(r); err != nil {
+		return err
+	}
+
 	if err := t.runSubTasks(r); err != nil {
 		return err
 	}
@@ -84,17 +212,24 @@ func (t *Task) shouldRun(r *run.Run) (bool, error) {
 		return true, nil
 	}
 
-	if err := r.When.Validate(t.Vars); err != nil {
+	if err := r.When.Validate(t.vars(), t.interpreter(r)); err != nil {
 		if !when.IsFailedCondition(err) {
 			return false, err
 		}
 
 		for _, command := range r.Command {
 			ui.PrintSkipped(command, err.Error())
+			ui.SkipCommand(t.Name, command, err.Error())
+		}
+
+		if r.Script != "" {
+			ui.PrintSkipped(r.Script, err.Error())
+			ui.SkipCommand(t.Name, r.Script, err.Error())
 		}
 
 		for _, subTaskName := range r.Task {
 			ui.PrintSkipped("task: "+subTaskName, err.Error())
+			ui.SkipTask(subTaskName, err.Error())
 		}
 
 		return false, nil
@@ -105,7 +240,10 @@ func (t *Task) shouldRun(r *run.Run) (bool, error) {
 
 func (t *Task) runCommands(r *run.Run) error {
 	for _, command := range r.Command {
-		if err := run.ExecCommand(command); err != nil {
+		record := ui.StartCommand(t.Name, command)
+		err := t.runCommand(r, command)
+		record.Finish(err)
+		if err != nil {
 			return err
 		}
 	}
@@ -113,6 +251,46 @@ func (t *Task) runCommands(r *run.Run) error {
 	return nil
 }
 
+// runCommand executes a single command, registering its output if needed.
+func (t *Task) runCommand(r *run.Run, command string) error {
+	if r.Register == "" {
+		return run.ExecCommand(t.Name, t.interpreter(r), command)
+	}
+
+	out, code, err := run.CaptureCommand(t.Name, t.interpreter(r), command)
+	if err != nil {
+		return err
+	}
+
+	t.registered[r.Register] = out
+	t.registered[r.Register+ExitCodeSuffix] = strconv.Itoa(code)
+
+	return nil
+}
+
+// runScript executes the script of a run item, if any.
+func (t *Task) runScript(r *run.Run) error {
+	if r.Script == "" {
+		return nil
+	}
+
+	record := ui.StartCommand(t.Name, r.Script)
+	err := run.ExecScript(t.Name, t.interpreter(r), r.Script)
+	record.Finish(err)
+
+	return err
+}
+
+// interpreter returns the interpreter for a run item, which is the one set
+// for the run item, if any, or the interpreter of the task.
+func (t *Task) interpreter(r *run.Run) shell.Interpreter {
+	if len(r.Interpreter) > 0 {
+		return r.Interpreter
+	}
+
+	return t.Interpreter
+}
+
 func (t *Task) runSubTasks(r *run.Run) error {
 	for _, subTaskName := range r.Task {
 		for _, subTask := range t.SubTasks {
@@ -126,3 +304,61 @@ func (t *Task) runSubTasks(r *run.Run) error {
 
 	return nil
 }
+
+// interpolate returns a copy of a run item with option values and values
+// registered by previous run items substituted in.
+func (t *Task) interpolate(r *run.Run) (*run.Run, error) {
+	text, err := yaml.Marshal(r)
+	if err != nil {
+		return nil, err
+	}
+
+	interpolated := new(run.Run)
+	if err := yaml.Unmarshal(text, interpolated); err != nil {
+		return nil, errors.Wrap(err, "could not copy run item")
+	}
+
+	// Templates are not interpolated, so that option values are only ever
+	// available as data and cannot change the template itself
+	var templates []string
+	if interpolated.Template {
+		templates = interpolated.Command
+		interpolated.Command = nil
+	}
+
+	context := fmt.Sprintf(`task "%s"`, t.Name)
+	warnings, err := interp.CheckUndefined(interpolated, t.vars(), context, t.Strict)
+	if err != nil {
+		return nil, err
+	}
+	for _, warning := range warnings {
+		ui.Warn(warning)
+	}
+
+	if err := interp.Struct(interpolated, t.vars()); err != nil {
+		return nil, err
+	}
+
+	for _, command := range templates {
+		rendered, err := t.render(command)
+		if err != nil {
+			return nil, err
+		}
+		interpolated.Command = append(interpolated.Command, rendered)
+	}
+
+	return interpolated, nil
+}
+
+// vars returns the option values and registered values available to a task.
+func (t *Task) vars() map[string]string {
+	vars := make(map[string]string, len(t.Vars)+len(t.registered))
+	for name, value := range t.Vars {
+		vars[name] = value
+	}
+	for name, value := range t.registered {
+		vars[name] = value
+	}
+
+	return vars
+}
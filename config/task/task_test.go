@@ -1,10 +1,15 @@
 package task
 
 import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
 	"testing"
 
 	"github.com/rliebz/tusk/config/marshal"
 	"github.com/rliebz/tusk/config/run"
+	"github.com/rliebz/tusk/config/shell"
 	"github.com/rliebz/tusk/config/when"
 	yaml "gopkg.in/yaml.v2"
 )
@@ -32,6 +37,20 @@ func TestTask_UnmarshalYAML(t *testing.T) {
 	}
 }
 
+func TestTask_HasName(t *testing.T) {
+	task := Task{Name: "test", Aliases: marshal.StringList{"t", "tests"}}
+
+	for _, name := range []string{"test", "t", "tests"} {
+		if !task.HasName(name) {
+			t.Errorf("HasName(%s): expected true, actual false", name)
+		}
+	}
+
+	if task.HasName("other") {
+		t.Error("HasName(other): expected false, actual true")
+	}
+}
+
 var shouldtests = []struct {
 	desc     string
 	input    *run.Run
@@ -91,3 +110,206 @@ func TestTask_runCommands(t *testing.T) {
 		)
 	}
 }
+
+func TestTask_Execute_register(t *testing.T) {
+	task := Task{Run: run.List{
+		{Command: marshal.StringList{"echo '  hello  '"}, Register: "greeting"},
+		{Command: marshal.StringList{"exit 3"}, Register: "failure"},
+		{Command: marshal.StringList{`test "${greeting}" = hello`}},
+		{
+			When: &when.When{
+				Equal: map[string]marshal.StringList{"failure-exit-code": {"3"}},
+			},
+			Command: marshal.StringList{`test "${failure-exit-code}" = 3`},
+		},
+		{Command: marshal.StringList{`test "$${greeting}" = ""`}},
+	}}
+
+	if err := task.Execute(); err != nil {
+		t.Fatalf("task.Execute(): unexpected error: %s", err)
+	}
+
+	expected := map[string]string{
+		"greeting":           "hello",
+		"greeting-exit-code": "0",
+		"failure":            "",
+		"failure-exit-code":  "3",
+	}
+
+	for name, value := range expected {
+		if actual := task.registered[name]; value != actual {
+			t.Errorf(
+				`task.Execute(): expected registered "%s" to be "%s", actual "%s"`,
+				name, value, actual,
+			)
+		}
+	}
+}
+
+func TestTask_Execute_interpreter(t *testing.T) {
+	task := Task{
+		Interpreter: shell.Interpreter{"sh", "-c", "echo task $0"},
+		Run: run.List{
+			{Command: marshal.StringList{"echo hello"}, Register: "task"},
+			{
+				Command:     marshal.StringList{"echo hello"},
+				Register:    "item",
+				Interpreter: shell.Interpreter{"sh", "-c"},
+			},
+		},
+	}
+
+	if err := task.Execute(); err != nil {
+		t.Fatalf("task.Execute(): unexpected error: %s", err)
+	}
+
+	expected := map[string]string{"task": "task echo hello", "item": "hello"}
+	for name, value := range expected {
+		if actual := task.registered[name]; value != actual {
+			t.Errorf(
+				`task.Execute(): expected registered "%s" to be "%s", actual "%s"`,
+				name, value, actual,
+			)
+		}
+	}
+}
+
+func TestTask_Execute_dir(t *testing.T) {
+	dir, err := ioutil.TempDir("", "tusk-task")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	defer os.RemoveAll(dir) // nolint: errcheck
+
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	task := Task{Dir: dir, Run: run.List{
+		{Command: marshal.StringList{"pwd -P"}, Register: "dir"},
+	}}
+
+	if err := task.Execute(); err != nil {
+		t.Fatalf("task.Execute(): unexpected error: %s", err)
+	}
+
+	expected, err := filepath.EvalSymlinks(dir)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if actual := task.registered["dir"]; expected != actual {
+		t.Errorf("task.Execute(): expected to run in %s, actual %s", expected, actual)
+	}
+
+	if actual, err := os.Getwd(); err != nil || wd != actual {
+		t.Errorf("task.Execute(): expected working directory %s to be restored, actual %s", wd, actual)
+	}
+}
+
+var rendertests = []struct {
+	command  string
+	vars     map[string]string
+	expected string
+}{
+	{`echo {{.Task.Name}}`, nil, "echo mytask"},
+	{`go test{{if .Options.verbose}} -v{{end}}`, map[string]string{"verbose": "true"}, "go test -v"},
+	{`go test{{if .Options.verbose}} -v{{end}}`, map[string]string{"verbose": "false"}, "go test"},
+	{
+		`{{range split "," .Options.pkgs}}go test {{.}}; {{end}}`,
+		map[string]string{"pkgs": "a, b,,c"},
+		"go test a; go test b; go test c; ",
+	},
+	{`echo {{split "," .Options.pkgs | join " "}}`, map[string]string{"pkgs": "a,b"}, "echo a b"},
+	{`echo {{quote .Options.name}}`, map[string]string{"name": "it's"}, `echo 'it'\''s'`},
+	{`echo {{.Options.name | default "World"}}`, map[string]string{"name": ""}, "echo World"},
+	{`echo {{.Env.TUSK_TEMPLATE_TEST}}`, nil, "echo set"},
+}
+
+func TestTask_render(t *testing.T) {
+	if err := os.Setenv("TUSK_TEMPLATE_TEST", "set"); err != nil {
+		t.Fatalf("os.Setenv(): unexpected error: %s", err)
+	}
+	defer os.Unsetenv("TUSK_TEMPLATE_TEST") // nolint: errcheck
+
+	for _, tt := range rendertests {
+		task := Task{Name: "mytask", Vars: tt.vars}
+
+		actual, err := task.render(tt.command)
+		if err != nil {
+			t.Errorf("task.render(%q): unexpected error: %s", tt.command, err)
+			continue
+		}
+
+		if tt.expected != actual {
+			t.Errorf(
+				"task.render(%q): expected %q, actual %q",
+				tt.command, tt.expected, actual,
+			)
+		}
+	}
+}
+
+func TestTask_render_errors(t *testing.T) {
+	task := Task{Name: "mytask"}
+
+	for _, command := range []string{`{{.Options.missing}}`, `{{if}}`} {
+		if _, err := task.render(command); err == nil {
+			t.Errorf("task.render(%q): expected error, actual nil", command)
+		}
+	}
+}
+
+func TestTask_interpolate_template(t *testing.T) {
+	if err := os.Setenv("TUSK_TEMPLATE_SECRET", "secret"); err != nil {
+		t.Fatalf("os.Setenv(): unexpected error: %s", err)
+	}
+	defer os.Unsetenv("TUSK_TEMPLATE_SECRET") // nolint: errcheck
+
+	task := Task{Name: "mytask", Vars: map[string]string{
+		"name":  "{{.Env.TUSK_TEMPLATE_SECRET}}",
+		"brace": "{{",
+	}}
+
+	r := &run.Run{
+		Command: marshal.StringList{
+			`echo ${name} {{quote .Options.name}}`,
+			`echo {{.Options.brace}}`,
+		},
+		Template: true,
+	}
+
+	actual, err := task.interpolate(r)
+	if err != nil {
+		t.Fatalf("task.interpolate(): unexpected error: %s", err)
+	}
+
+	expected := marshal.StringList{
+		`echo ${name} '{{.Env.TUSK_TEMPLATE_SECRET}}'`,
+		`echo {{`,
+	}
+	if !reflect.DeepEqual(expected, actual.Command) {
+		t.Errorf("task.interpolate(): expected %q, actual %q", expected, actual.Command)
+	}
+}
+
+func TestTask_WatchPatterns(t *testing.T) {
+	sub := &Task{Dir: "/sub", Watch: marshal.StringList{"*.go", "/abs/*.txt"}}
+	task := Task{
+		Dir:      "/root",
+		Watch:    marshal.StringList{"src/**/*.${ext}", "/abs/*.txt"},
+		Vars:     map[string]string{"ext": "go"},
+		SubTasks: []*Task{sub},
+	}
+
+	actual, err := task.WatchPatterns()
+	if err != nil {
+		t.Fatalf("task.WatchPatterns(): unexpected error: %s", err)
+	}
+
+	expected := []string{"/root/src/**/*.go", "/abs/*.txt", "/sub/*.go"}
+	if !reflect.DeepEqual(expected, actual) {
+		t.Errorf("task.WatchPatterns(): expected %v, actual %v", expected, actual)
+	}
+}
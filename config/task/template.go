@@ -0,0 +1,119 @@
+package task
+
+import (
+	"bytes"
+	"os"
+	"strings"
+	"text/template"
+
+	"github.com/pkg/errors"
+	"github.com/rliebz/tusk/interp"
+)
+
+// templateFuncs are the functions available to command templates.
+var templateFuncs = template.FuncMap{
+	"default": defaultValue,
+	"join":    join,
+	"quote":   interp.Quote,
+	"split":   split,
+}
+
+// templateData is the data available to command templates.
+type templateData struct {
+	Options map[string]interface{}
+	Env     map[string]string
+	Task    templateTask
+}
+
+// templateTask is the metadata for the task available to command templates.
+type templateTask struct {
+	Name        string
+	Usage       string
+	Description string
+}
+
+// render executes a command as a text/template.
+func (t *Task) render(command string) (string, error) {
+	tmpl, err := template.New(t.Name).
+		Option("missingkey=error").
+		Funcs(templateFuncs).
+		Parse(command)
+	if err != nil {
+		return "", errors.Wrap(err, "could not parse command template")
+	}
+
+	buf := new(bytes.Buffer)
+	if err := tmpl.Execute(buf, t.templateData()); err != nil {
+		return "", errors.Wrap(err, "could not execute command template")
+	}
+
+	return buf.String(), nil
+}
+
+func (t *Task) templateData() templateData {
+	data := templateData{
+		Options: make(map[string]interface{}),
+		Env:     make(map[string]string),
+		Task: templateTask{
+			Name:        t.Name,
+			Usage:       t.Usage,
+			Description: t.Description,
+		},
+	}
+
+	// Booleans are converted so that they can be used in conditionals
+	for name, value := range t.vars() {
+		switch value {
+		case "true":
+			data.Options[name] = true
+		case "false":
+			data.Options[name] = false
+		default:
+			data.Options[name] = value
+		}
+	}
+
+	for _, kv := range os.Environ() {
+		parts := strings.SplitN(kv, "=", 2)
+		if len(parts) == 2 {
+			data.Env[parts[0]] = parts[1]
+		}
+	}
+
+	return data
+}
+
+// defaultValue returns the default if the value is empty, false, or unset.
+func defaultValue(def interface{}, value interface{}) interface{} {
+	switch v := value.(type) {
+	case nil:
+		return def
+	case string:
+		if v == "" {
+			return def
+		}
+	case bool:
+		if !v {
+			return def
+		}
+	}
+
+	return value
+}
+
+// join joins a list of items with a separator.
+func join(sep string, items []string) string {
+	return strings.Join(items, sep)
+}
+
+// split splits a value into a list of items, ignoring empty items.
+func split(sep string, value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, sep) {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+
+	return items
+}
@@ -0,0 +1,326 @@
+package config
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/rliebz/tusk/config/marshal"
+	"github.com/rliebz/tusk/config/option"
+	"github.com/rliebz/tusk/config/task"
+	"github.com/rliebz/tusk/config/when"
+	"github.com/rliebz/tusk/interp"
+)
+
+// Problem is a single issue found in a config file.
+type Problem struct {
+	// Path is the location of the problem, such as tasks.test.options.verbose.
+	Path string
+	// Line and Column are the position in the config file, or 0 if unknown.
+	Line    int
+	Column  int
+	Message string
+}
+
+// String returns the problem formatted for display.
+func (p Problem) String() string {
+	if p.Line == 0 {
+		return fmt.Sprintf("%s: %s", p.Path, p.Message)
+	}
+
+	return fmt.Sprintf(
+		"line %d, column %d: %s: %s", p.Line, p.Column, p.Path, p.Message,
+	)
+}
+
+// Validate checks every task and option in a config file, returning all of
+// the problems found rather than stopping at the first one. An error is only
+// returned when the config file cannot be parsed at all.
+func Validate(text []byte) ([]Problem, error) {
+	cfg, err := Parse(text)
+	if err != nil {
+		return nil, err
+	}
+
+	return validate(text, cfg, cfg), nil
+}
+
+// Validate checks the config file the same way as the Validate function.
+// Tasks and options inherited from parent config files can be referenced,
+// but only problems in the config file itself are reported.
+func (m *Metadata) Validate() ([]Problem, error) {
+	own, err := parse(m.CfgText)
+	if err != nil {
+		return nil, err
+	}
+
+	cfg, err := m.Parse()
+	if err != nil {
+		return nil, err
+	}
+
+	return validate(m.CfgText, own, cfg), nil
+}
+
+// validate checks the tasks and options defined by own, which are found in
+// the text passed, in the context of the full config.
+func validate(text []byte, own *Config, cfg *Config) []Problem {
+	v := validator{text: text, cfg: cfg}
+
+	global := make(map[string]bool)
+	for name := range cfg.Options {
+		global[name] = true
+	}
+
+	for _, name := range sortedOptionNames(own.Options) {
+		v.checkOption(own.Options[name], global, "options", name)
+	}
+
+	var taskNames []string
+	for name := range own.Tasks {
+		taskNames = append(taskNames, name)
+	}
+	sort.Strings(taskNames)
+
+	for _, name := range taskNames {
+		v.checkTask(cfg.Tasks[name])
+	}
+
+	sort.SliceStable(v.problems, func(i, j int) bool {
+		return v.problems[i].Line < v.problems[j].Line
+	})
+
+	return v.problems
+}
+
+type validator struct {
+	text     []byte
+	cfg      *Config
+	problems []Problem
+}
+
+// report adds a problem found at a path in the config file.
+func (v *validator) report(path []string, format string, a ...interface{}) {
+	line, column := Locate(v.text, path...)
+	v.problems = append(v.problems, Problem{
+		Path:    strings.Join(path, "."),
+		Line:    line,
+		Column:  column,
+		Message: fmt.Sprintf(format, a...),
+	})
+}
+
+func (v *validator) checkTask(t *task.Task) {
+	path := []string{"tasks", t.Name}
+	known := v.knownNames(t)
+
+	for _, name := range sortedOptionNames(t.Options) {
+		v.checkOption(t.Options[name], known, append(path, "options", name)...)
+	}
+
+	valid := true
+	for i, r := range t.Run {
+		runPath := append(path, "run", strconv.Itoa(i))
+
+		for _, subTaskName := range r.Task {
+			if _, ok := v.cfg.Tasks[subTaskName]; !ok {
+				v.report(runPath, `sub-task "%s" is not defined`, subTaskName)
+				valid = false
+			} else if v.reaches(subTaskName, t.Name, make(map[string]bool)) {
+				v.report(runPath, `sub-task "%s" creates a cycle`, subTaskName)
+				valid = false
+			}
+		}
+
+		v.checkWhen(r.When, known, append(runPath, "when")...)
+
+		whenNames := make(map[string]bool)
+		for _, name := range r.When.Dependencies() {
+			whenNames[name] = true
+		}
+		for _, name := range r.Dependencies() {
+			if !whenNames[name] && !known[name] {
+				v.report(runPath, `template references undefined option "%s"`, name)
+			}
+		}
+
+		// Templates are not interpolated when run
+		interpolated := *r
+		if interpolated.Template {
+			interpolated.Command = nil
+		}
+		v.checkInterpolation(&interpolated, known, runPath...)
+	}
+
+	if !valid || !v.isComplete(t.Name, make(map[string]bool)) {
+		return
+	}
+
+	if err := AddSubTasks(v.cfg, t); err != nil {
+		v.report(path, "%s", err)
+		return
+	}
+
+	if _, err := v.cfg.FindAllOptions(t); err != nil {
+		v.report(path, "%s", err)
+	}
+}
+
+func (v *validator) checkOption(opt *option.Option, known map[string]bool, path ...string) {
+	if !opt.HasValidType() {
+		v.report(append(path, "type"), `unsupported type "%s"`, opt.Type)
+	}
+
+	for i, value := range opt.DefaultValues {
+		v.checkWhen(&value.When, known, append(path, "default", strconv.Itoa(i), "when")...)
+	}
+
+	v.checkInterpolation(opt, known, path...)
+}
+
+func (v *validator) checkWhen(w *when.When, known map[string]bool, path ...string) {
+	if w == nil {
+		return
+	}
+
+	clauses := []struct {
+		key    string
+		values map[string]marshal.StringList
+	}{
+		{"equal", w.Equal},
+		{"not_equal", w.NotEqual},
+	}
+
+	for _, clause := range clauses {
+		var names []string
+		for name := range clause.values {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		for _, name := range names {
+			if !known[name] {
+				v.report(
+					append(path, clause.key, name),
+					`when clause references undefined option "%s"`, name,
+				)
+			}
+		}
+	}
+}
+
+// checkInterpolation reports every interpolated name that does not match an
+// option available in the current context. References are found the same way
+// as when a task is run, so those meant for the shell are not reported.
+func (v *validator) checkInterpolation(item interface{}, known map[string]bool, path ...string) {
+	vars := make(map[string]string, len(known))
+	for name := range known {
+		vars[name] = ""
+	}
+
+	names, err := interp.UndefinedIn(item, vars)
+	if err != nil {
+		v.report(path, "%s", err)
+		return
+	}
+
+	for _, name := range names {
+		v.report(path, `"${%s}" does not match any option`, name)
+	}
+}
+
+// knownNames returns the name of every value that can be referenced by a
+// task, including options defined by its sub-tasks and registered output.
+func (v *validator) knownNames(t *task.Task) map[string]bool {
+	known := make(map[string]bool)
+	for name := range v.cfg.Options {
+		known[name] = true
+	}
+
+	for _, r := range t.Run {
+		if r.Register != "" {
+			known[r.Register] = true
+			known[r.Register+task.ExitCodeSuffix] = true
+		}
+	}
+
+	visited := make(map[string]bool)
+	var addOptions func(t *task.Task)
+	addOptions = func(t *task.Task) {
+		if visited[t.Name] {
+			return
+		}
+		visited[t.Name] = true
+
+		for name := range t.Options {
+			known[name] = true
+		}
+
+		for _, r := range t.Run {
+			for _, subTaskName := range r.Task {
+				if subTask, ok := v.cfg.Tasks[subTaskName]; ok {
+					addOptions(subTask)
+				}
+			}
+		}
+	}
+	addOptions(t)
+
+	return known
+}
+
+// reaches tells if a task refers to the target task, directly or through any
+// of its sub-tasks.
+func (v *validator) reaches(taskName, target string, visited map[string]bool) bool {
+	if taskName == target {
+		return true
+	}
+
+	t, ok := v.cfg.Tasks[taskName]
+	if !ok || visited[taskName] {
+		return false
+	}
+	visited[taskName] = true
+
+	for _, r := range t.Run {
+		for _, subTaskName := range r.Task {
+			if v.reaches(subTaskName, target, visited) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// isComplete tells if every sub-task of a task is defined and free of cycles.
+func (v *validator) isComplete(taskName string, visiting map[string]bool) bool {
+	t, ok := v.cfg.Tasks[taskName]
+	if !ok || visiting[taskName] {
+		return false
+	}
+
+	visiting[taskName] = true
+	defer delete(visiting, taskName)
+
+	for _, r := range t.Run {
+		for _, subTaskName := range r.Task {
+			if !v.isComplete(subTaskName, visiting) {
+				return false
+			}
+		}
+	}
+
+	return true
+}
+
+func sortedOptionNames(options map[string]*option.Option) []string {
+	var names []string
+	for name := range options {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+}
@@ -0,0 +1,99 @@
+package config
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestValidate(t *testing.T) {
+	cfgText := []byte(`
+options:
+  global:
+    type: strin
+tasks:
+  one:
+    options:
+      local:
+        default:
+          - when:
+              equal: {missing: true}
+            value: ${global}
+    run:
+      - when:
+          not_equal:
+            typo: false
+        command: echo ${local} ${typo} $${escaped}
+      - task: undefined
+  two:
+    run:
+      - command: echo hi
+        register: out
+      - command: echo ${out} ${out-exit-code} ${local}
+      - task: one
+  three:
+    run:
+      task: four
+  four:
+    run:
+      task: three
+`)
+
+	problems, err := Validate(cfgText)
+	if err != nil {
+		t.Fatalf("Validate(cfgText): unexpected error: %s", err)
+	}
+
+	expected := []Problem{
+		{"options.global.type", 4, 5, `unsupported type "strin"`},
+		{"tasks.one.options.local.default.0.when.equal.missing", 11, 15, `when clause references undefined option "missing"`},
+		{"tasks.one.run.0", 14, 7, `"${typo}" does not match any option`},
+		{"tasks.one.run.0.when.not_equal.typo", 16, 13, `when clause references undefined option "typo"`},
+		{"tasks.one.run.1", 18, 7, `sub-task "undefined" is not defined`},
+		{"tasks.three.run.0", 26, 5, `sub-task "four" creates a cycle`},
+		{"tasks.four.run.0", 29, 5, `sub-task "three" creates a cycle`},
+	}
+
+	if !reflect.DeepEqual(expected, problems) {
+		t.Errorf("Validate(cfgText):\nexpected: %v\nactual: %v", expected, problems)
+	}
+}
+
+func TestValidate_valid(t *testing.T) {
+	cfgText := []byte(`
+options:
+  foo:
+    type: bool
+tasks:
+  one:
+    options:
+      bar:
+        default: ${foo}
+    run:
+      - when:
+          equal: {foo: true}
+        command: echo ${bar}
+  two:
+    run:
+      - task: one
+      - when:
+          equal: {bar: baz}
+        command: "{{.Options.bar}} ${HOME}"
+        template: true
+      - command: echo ${HOME:-/tmp} ${bar:-default} ${USER:+set}
+`)
+
+	problems, err := Validate(cfgText)
+	if err != nil {
+		t.Fatalf("Validate(cfgText): unexpected error: %s", err)
+	}
+
+	if len(problems) != 0 {
+		t.Errorf("Validate(cfgText): expected no problems, got %v", problems)
+	}
+}
+
+func TestValidate_parse_error(t *testing.T) {
+	if _, err := Validate([]byte("tasks: [")); err == nil {
+		t.Error("Validate(invalid yaml): expected error, got nil")
+	}
+}
@@ -2,11 +2,11 @@ package when
 
 import (
 	"os"
-	"os/exec"
 	"runtime"
 	"strings"
 
 	"github.com/rliebz/tusk/config/marshal"
+	"github.com/rliebz/tusk/config/shell"
 )
 
 // When defines the conditions for running a task.
@@ -46,8 +46,9 @@ func (w *When) Dependencies() []string {
 	return options
 }
 
-// Validate returns an error if any when clauses fail.
-func (w *When) Validate(vars map[string]string) error {
+// Validate returns an error if any when clauses fail. Commands are run with
+// the interpreter passed.
+func (w *When) Validate(vars map[string]string, interpreter shell.Interpreter) error {
 	if w == nil {
 		return nil
 	}
@@ -66,7 +67,7 @@ func (w *When) Validate(vars map[string]string) error {
 	}
 
 	for _, command := range w.Command {
-		if err := testCommand(command); err != nil {
+		if err := testCommand(command, interpreter); err != nil {
 			return newCondFailErrorf(`test failed: %s`, command)
 		}
 	}
@@ -120,8 +121,8 @@ func normalizeOS(os string) string {
 	return lower
 }
 
-func testCommand(command string) error {
-	_, err := exec.Command("sh", "-c", command).Output() // nolint: gas
+func testCommand(command string, interpreter shell.Interpreter) error {
+	_, err := interpreter.Command(command).Output()
 	return err
 }
 
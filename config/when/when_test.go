@@ -181,7 +181,7 @@ var validatetests = []struct {
 
 func TestWhen_Validate(t *testing.T) {
 	for _, tt := range validatetests {
-		err := tt.when.Validate(tt.options)
+		err := tt.when.Validate(tt.options, nil)
 		didErr := err != nil
 		if tt.shouldErr != didErr {
 			t.Errorf(
@@ -3,7 +3,7 @@ package whentest
 import "testing"
 
 func TestTrue(t *testing.T) {
-	if err := True.Validate(nil); err != nil {
+	if err := True.Validate(nil, nil); err != nil {
 		t.Errorf(
 			"whentest.True did not pass validation. Unexpected err: %s", err,
 		)
@@ -11,7 +11,7 @@ func TestTrue(t *testing.T) {
 }
 
 func TestFalse(t *testing.T) {
-	if err := False.Validate(nil); err == nil {
+	if err := False.Validate(nil, nil); err == nil {
 		t.Errorf(
 			"whentest.False passed validation but should have errored",
 		)
@@ -0,0 +1,96 @@
+package interp
+
+import (
+	"fmt"
+	"strings"
+)
+
+// The operators available for handling empty or unset values.
+const (
+	operatorDefault   = ":-"
+	operatorRequired  = ":?"
+	operatorAlternate = ":+"
+)
+
+// filters are the functions that can be applied to a value with `|`.
+var filters = map[string]func(string) string{
+	"lower": strings.ToLower,
+	"quote": Quote,
+	"trim":  strings.TrimSpace,
+	"upper": strings.ToUpper,
+}
+
+// expression is a single variable reference, such as ${name:-word|upper}.
+type expression struct {
+	name     string
+	operator string
+	word     string
+	filters  []string
+}
+
+// parseExpression parses the modifiers that follow a variable name. If the
+// modifiers are not recognized, the reference is not considered valid.
+func parseExpression(name string, modifiers string) (expression, bool) {
+	e := expression{name: name}
+
+	parts := strings.Split(modifiers, "|")
+	e.filters = parts[1:]
+
+	if op := parts[0]; op != "" {
+		if len(op) < 2 {
+			return e, false
+		}
+
+		switch op[:2] {
+		case operatorDefault, operatorRequired, operatorAlternate:
+			e.operator, e.word = op[:2], op[2:]
+		default:
+			return e, false
+		}
+	}
+
+	return e, true
+}
+
+// evaluate returns the value of the expression. If the variable is unknown,
+// ok will be false, so that the reference is left for the shell to expand.
+func (e expression) evaluate(vars map[string]string) (value string, ok bool, err error) {
+	value, isSet := vars[e.name]
+	if !isSet {
+		return "", false, nil
+	}
+
+	switch e.operator {
+	case operatorDefault:
+		if value == "" {
+			value = e.word
+		}
+	case operatorRequired:
+		if value == "" {
+			message := e.word
+			if message == "" {
+				message = "value is empty or not set"
+			}
+			return "", false, fmt.Errorf("%s: %s", e.name, message)
+		}
+	case operatorAlternate:
+		if value != "" {
+			value = e.word
+		}
+	}
+
+	for _, name := range e.filters {
+		f, found := filters[name]
+		if !found {
+			return "", false, fmt.Errorf(`unknown function "%s" for %s`, name, e.name)
+		}
+		value = f(value)
+	}
+
+	return value, true, nil
+}
+
+// Quote safely quotes a value as a single argument for a POSIX shell.
+func Quote(s string) string {
+	return "'" + strings.Replace(s, "'", `'\''`, -1) + "'"
+}
@@ -8,6 +8,74 @@ import (
 
 var escSeq = []byte("{UNLIKELY_ESCAPE_SEQUENCE}")
 
+// pattern matches either an escape sequence or a variable reference, which
+// may include modifiers after the name.
+var pattern = regexp.MustCompile(`\$\$|\$\{([\w-]+)([^}]*)\}`)
+
+// String replaces every variable reference in text with its value in a single
+// pass, and escapes all instances of $$ with $. Values are inserted verbatim,
+// and references to unknown variables are left untouched.
+//
+// References may use the shell-like operators ${name:-default},
+// ${name:?error message}, and ${name:+alternate}, and may be passed through
+// the functions upper, lower, trim, and quote, as in ${name|trim|quote}.
+// Operators only apply to known variables, so that other references, such as
+// ${HOME:-/tmp}, are left for the shell.
+func String(text string, vars map[string]string) (string, error) {
+	var err error
+
+	output := pattern.ReplaceAllStringFunc(text, func(match string) string {
+		if err != nil {
+			return match
+		}
+
+		if match == "$$" {
+			return "$"
+		}
+
+		groups := pattern.FindStringSubmatch(match)
+		e, ok := parseExpression(groups[1], groups[2])
+		if !ok {
+			return match
+		}
+
+		value, ok, evalErr := e.evaluate(vars)
+		if evalErr != nil {
+			err = evalErr
+			return match
+		}
+
+		if !ok {
+			return match
+		}
+
+		return value
+	})
+
+	if err != nil {
+		return "", err
+	}
+
+	return output, nil
+}
+
+// Names returns the name of every variable referenced in text, in order.
+// Escaped references are ignored.
+func Names(text string) []string {
+	var names []string
+	for _, groups := range pattern.FindAllStringSubmatch(text, -1) {
+		if groups[1] == "" {
+			continue
+		}
+
+		if _, ok := parseExpression(groups[1], groups[2]); ok {
+			names = append(names, groups[1])
+		}
+	}
+
+	return names
+}
+
 // Escape escapes all instances of $$ with $.
 func Escape(text []byte) []byte {
 	return bytes.Replace(text, []byte("$$"), []byte("$"), -1)
@@ -55,7 +123,7 @@ func Contains(text []byte, name string) (bool, error) {
 
 // CompileGeneric returns the regexp pattern to identify a potential variable.
 func CompileGeneric() *regexp.Regexp {
-	return regexp.MustCompile(`\${(\w+)}`)
+	return regexp.MustCompile(`\${([\w-]+)}`)
 }
 
 // Compile returns the regexp pattern for a given variable name.
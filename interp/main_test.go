@@ -2,6 +2,7 @@ package interp
 
 import (
 	"bytes"
+	"reflect"
 	"testing"
 )
 
@@ -126,3 +127,91 @@ func TestContains(t *testing.T) {
 		}
 	}
 }
+
+var stringtests = []struct {
+	input    string
+	vars     map[string]string
+	expected string
+}{
+	{"${foo}", map[string]string{"foo": "bar"}, "bar"},
+	{"${foo-bar}", map[string]string{"foo-bar": "baz"}, "baz"},
+	{"${foo}${bar}", map[string]string{"foo": "bar"}, "bar${bar}"},
+	{"$${foo}", map[string]string{"foo": "bar"}, "${foo}"},
+	{"$$$${foo}", map[string]string{"foo": "bar"}, "$${foo}"},
+	{"$$${foo}", map[string]string{"foo": "bar"}, "$bar"},
+	{"${foo}", map[string]string{"foo": "${bar}"}, "${bar}"},
+	{"${foo}", map[string]string{"foo": "a: 'b'\n$$"}, "a: 'b'\n$$"},
+	{"${foo:0:3}", map[string]string{"foo": "bar"}, "${foo:0:3}"},
+	{"${foo:-baz}", map[string]string{"foo": "bar"}, "bar"},
+	{"${foo:-baz}", map[string]string{"foo": ""}, "baz"},
+	{"${foo:-baz}", map[string]string{}, "${foo:-baz}"},
+	{"${foo:-}", map[string]string{"foo": ""}, ""},
+	{"${foo:+baz}", map[string]string{"foo": "bar"}, "baz"},
+	{"${foo:+baz}", map[string]string{"foo": ""}, ""},
+	{"${foo:+baz}", map[string]string{}, "${foo:+baz}"},
+	{"${foo:?oops}", map[string]string{"foo": "bar"}, "bar"},
+	{"${foo|upper}", map[string]string{"foo": "Bar"}, "BAR"},
+	{"${foo|lower}", map[string]string{"foo": "Bar"}, "bar"},
+	{"${foo|trim}", map[string]string{"foo": " bar\n"}, "bar"},
+	{"${foo|quote}", map[string]string{"foo": "it's"}, `'it'\''s'`},
+	{"${foo|trim|upper}", map[string]string{"foo": " bar "}, "BAR"},
+	{"${foo:-baz|upper}", map[string]string{"foo": ""}, "BAZ"},
+	{"${foo:-baz|upper}", map[string]string{}, "${foo:-baz|upper}"},
+	{"${foo:?}", map[string]string{}, "${foo:?}"},
+	{"${foo|upper}", map[string]string{}, "${foo|upper}"},
+	{"$${foo:-baz}", map[string]string{}, "${foo:-baz}"},
+}
+
+func TestString(t *testing.T) {
+	for _, tt := range stringtests {
+		actual, err := String(tt.input, tt.vars)
+		if err != nil {
+			t.Errorf("String(%q, %v): unexpected error: %s", tt.input, tt.vars, err)
+			continue
+		}
+
+		if tt.expected != actual {
+			t.Errorf(
+				"String(%q, %v): expected: %q, actual: %q",
+				tt.input, tt.vars, tt.expected, actual,
+			)
+		}
+	}
+}
+
+var stringerrortests = []struct {
+	input    string
+	vars     map[string]string
+	expected string
+}{
+	{"${foo:?oops}", map[string]string{"foo": ""}, "foo: oops"},
+	{"${foo:?}", map[string]string{"foo": ""}, "foo: value is empty or not set"},
+	{"${foo|fake}", map[string]string{"foo": "bar"}, `unknown function "fake" for foo`},
+}
+
+func TestString_errors(t *testing.T) {
+	for _, tt := range stringerrortests {
+		_, err := String(tt.input, tt.vars)
+		if err == nil {
+			t.Errorf("String(%q, %v): expected error, actual nil", tt.input, tt.vars)
+			continue
+		}
+
+		if tt.expected != err.Error() {
+			t.Errorf(
+				"String(%q, %v): expected error: %q, actual: %q",
+				tt.input, tt.vars, tt.expected, err,
+			)
+		}
+	}
+}
+
+func TestNames(t *testing.T) {
+	input := "${foo} $${bar} ${baz-qux} $qux ${foo:-x|upper} ${quux:0:1}"
+	expected := []string{"foo", "baz-qux", "foo"}
+
+	actual := Names(input)
+	if !reflect.DeepEqual(expected, actual) {
+		t.Errorf("Names(%q): expected: %v, actual: %v", input, expected, actual)
+	}
+}
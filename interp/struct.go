@@ -0,0 +1,65 @@
+package interp
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// Struct interpolates every string reachable from a pointer to a struct in
+// place. Unexported fields and fields tagged with `yaml:"-"` are skipped, so
+// only values that are read from a config file are modified.
+func Struct(v interface{}, vars map[string]string) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() {
+		return fmt.Errorf("cannot interpolate non-pointer value %T", v)
+	}
+
+	return walkStrings(rv, func(s string) (string, error) { return String(s, vars) })
+}
+
+// walkStrings applies a function to every settable string within a value.
+func walkStrings(v reflect.Value, f func(string) (string, error)) error {
+	switch v.Kind() {
+	case reflect.Ptr:
+		if !v.IsNil() {
+			return walkStrings(v.Elem(), f)
+		}
+	case reflect.Struct:
+		t := v.Type()
+		for i := 0; i < v.NumField(); i++ {
+			field := t.Field(i)
+			if field.PkgPath != "" || field.Tag.Get("yaml") == "-" {
+				continue
+			}
+			if err := walkStrings(v.Field(i), f); err != nil {
+				return err
+			}
+		}
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			if err := walkStrings(v.Index(i), f); err != nil {
+				return err
+			}
+		}
+	case reflect.Map:
+		// Map values are not addressable, so they must be copied and replaced
+		for _, key := range v.MapKeys() {
+			elem := reflect.New(v.Type().Elem()).Elem()
+			elem.Set(v.MapIndex(key))
+			if err := walkStrings(elem, f); err != nil {
+				return err
+			}
+			v.SetMapIndex(key, elem)
+		}
+	case reflect.String:
+		if v.CanSet() {
+			s, err := f(v.String())
+			if err != nil {
+				return err
+			}
+			v.SetString(s)
+		}
+	}
+
+	return nil
+}
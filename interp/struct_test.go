@@ -0,0 +1,58 @@
+package interp
+
+import (
+	"reflect"
+	"testing"
+)
+
+type nested struct {
+	Value string
+}
+
+type structHolder struct {
+	String  string
+	List    []string
+	Map     map[string][]string
+	Pointer *nested
+	Nested  nested
+	Skipped string `yaml:"-"`
+	private string
+}
+
+func TestStruct(t *testing.T) {
+	vars := map[string]string{"foo": "bar"}
+
+	actual := &structHolder{
+		String:  "${foo}",
+		List:    []string{"${foo}", "$${foo}"},
+		Map:     map[string][]string{"${foo}": {"${foo}"}},
+		Pointer: &nested{Value: "${foo}"},
+		Nested:  nested{Value: "${foo}"},
+		Skipped: "${foo}",
+		private: "${foo}",
+	}
+
+	expected := &structHolder{
+		String:  "bar",
+		List:    []string{"bar", "${foo}"},
+		Map:     map[string][]string{"${foo}": {"bar"}},
+		Pointer: &nested{Value: "bar"},
+		Nested:  nested{Value: "bar"},
+		Skipped: "${foo}",
+		private: "${foo}",
+	}
+
+	if err := Struct(actual, vars); err != nil {
+		t.Fatalf("Struct(): unexpected error: %s", err)
+	}
+
+	if !reflect.DeepEqual(expected, actual) {
+		t.Errorf("Struct():\nexpected: %#v\nactual: %#v", expected, actual)
+	}
+}
+
+func TestStruct_non_pointer(t *testing.T) {
+	if err := Struct(structHolder{}, nil); err == nil {
+		t.Error("Struct(non-pointer): expected error, actual nil")
+	}
+}
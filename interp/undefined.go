@@ -0,0 +1,77 @@
+package interp
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+)
+
+// Undefined returns the name of every variable referenced in text that is not
+// defined in vars, in order. Escaped references are ignored, as are references
+// with an operator to handle unset values, such as ${name:-default}.
+func Undefined(text string, vars map[string]string) []string {
+	var names []string
+	for _, groups := range pattern.FindAllStringSubmatch(text, -1) {
+		if groups[1] == "" {
+			continue
+		}
+
+		e, ok := parseExpression(groups[1], groups[2])
+		if !ok || e.operator != "" {
+			continue
+		}
+
+		if _, ok := vars[e.name]; !ok {
+			names = append(names, e.name)
+		}
+	}
+
+	return names
+}
+
+// UndefinedIn returns the name of every undefined variable referenced in
+// every string reachable from a pointer to a struct, which should be checked
+// before it is interpolated. Each name is returned once, in order.
+func UndefinedIn(v interface{}, vars map[string]string) ([]string, error) {
+	var names []string
+	seen := make(map[string]bool)
+
+	err := walkStrings(reflect.ValueOf(v), func(s string) (string, error) {
+		for _, name := range Undefined(s, vars) {
+			if !seen[name] {
+				seen[name] = true
+				names = append(names, name)
+			}
+		}
+		return s, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return names, nil
+}
+
+// CheckUndefined looks for references to undefined variables in every string
+// reachable from a pointer to a struct, which should be checked before it is
+// interpolated. A warning is returned for each one found, or if strict is set,
+// an error is returned instead. The context describes where the struct came
+// from in messages, such as `task "build"`.
+func CheckUndefined(
+	v interface{}, vars map[string]string, context string, strict bool,
+) (warnings []string, err error) {
+	names, err := UndefinedIn(v, vars)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, name := range names {
+		message := fmt.Sprintf(`undefined option "%s" referenced in %s`, name, context)
+		if strict {
+			return nil, errors.New(message)
+		}
+		warnings = append(warnings, message)
+	}
+
+	return warnings, nil
+}
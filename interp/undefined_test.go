@@ -0,0 +1,67 @@
+package interp
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestUndefined(t *testing.T) {
+	input := "${foo} ${bar} $${baz} ${qux:-default} ${qux:+alternate} ${bar|upper}"
+	vars := map[string]string{"foo": ""}
+	expected := []string{"bar", "bar"}
+
+	actual := Undefined(input, vars)
+	if !reflect.DeepEqual(expected, actual) {
+		t.Errorf("Undefined(%q, %v): expected: %v, actual: %v", input, vars, expected, actual)
+	}
+}
+
+func TestUndefinedIn(t *testing.T) {
+	vars := map[string]string{"foo": "bar"}
+	holder := &structHolder{
+		String:  "${foo} $${escaped} ${other}",
+		List:    []string{"${typo}", "${typo}"},
+		Skipped: "${skipped}",
+	}
+
+	actual, err := UndefinedIn(holder, vars)
+	if err != nil {
+		t.Fatalf("UndefinedIn(): unexpected error: %s", err)
+	}
+
+	expected := []string{"other", "typo"}
+	if !reflect.DeepEqual(expected, actual) {
+		t.Errorf("UndefinedIn(): expected: %v, actual: %v", expected, actual)
+	}
+}
+
+func TestCheckUndefined(t *testing.T) {
+	vars := map[string]string{"foo": "bar"}
+	holder := &structHolder{
+		String: "${foo} $${escaped}",
+		List:   []string{"${typo}", "${typo}"},
+	}
+
+	message := `undefined option "typo" referenced in test`
+
+	warnings, err := CheckUndefined(holder, vars, "test", false)
+	if err != nil {
+		t.Errorf("CheckUndefined(): unexpected error: %s", err)
+	}
+	if expected := []string{message}; !reflect.DeepEqual(expected, warnings) {
+		t.Errorf("CheckUndefined(): expected warnings %q, actual %q", expected, warnings)
+	}
+
+	_, err = CheckUndefined(holder, vars, "test", true)
+	if err == nil {
+		t.Fatal("CheckUndefined() in strict mode: expected error, actual nil")
+	}
+	if message != err.Error() {
+		t.Errorf("CheckUndefined(): expected error %q, actual %q", message, err)
+	}
+
+	holder.List = nil
+	if warnings, err := CheckUndefined(holder, vars, "test", true); err != nil || len(warnings) > 0 {
+		t.Errorf("CheckUndefined() with escaped reference: unexpected warnings %q, error: %v", warnings, err)
+	}
+}
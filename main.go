@@ -1,11 +1,13 @@
 package main
 
 import (
+	"io"
 	"os"
 	"os/exec"
 	"syscall"
 
 	"github.com/rliebz/tusk/appcli"
+	"github.com/rliebz/tusk/config"
 	"github.com/rliebz/tusk/ui"
 )
 
@@ -30,36 +32,87 @@ func main() {
 		ui.Verbosity = meta.Verbosity
 	}
 
-	if err = os.Chdir(meta.Directory); err != nil {
+	ui.PrefixOutput = meta.PrefixOutput
+
+	events, err := setupEvents(meta)
+	if err != nil {
 		ui.Error(err)
 		os.Exit(1)
 	}
 
+	status := run(meta)
+
+	// Deferred calls are skipped by os.Exit, so the file is closed here
+	if events != nil {
+		if err := events.Close(); err != nil {
+			ui.Error(err)
+			status = 1
+		}
+	}
+
+	os.Exit(status)
+}
+
+// run runs the app and returns the exit code.
+func run(meta *config.Metadata) int {
+	if err := os.Chdir(meta.Directory); err != nil {
+		ui.Error(err)
+		return 1
+	}
+
 	if meta.PrintVersion && !meta.PrintHelp {
 		ui.Println(version)
-		os.Exit(0)
+		return 0
 	}
 
 	app, err := appcli.NewApp(meta)
 	if err != nil {
 		ui.Error(err)
-		os.Exit(1)
+		return 1
 	}
 
 	if meta.PrintHelp {
 		appcli.ShowAppHelp(app)
-		os.Exit(0)
+		return 0
 	}
 
 	if err := app.Run(os.Args); err != nil {
 		if exitErr, ok := err.(*exec.ExitError); ok {
 			ws := exitErr.Sys().(syscall.WaitStatus)
-			os.Exit(ws.ExitStatus())
-		} else {
-			ui.Error(err)
-			os.Exit(1)
+			return ws.ExitStatus()
 		}
+		ui.Error(err)
+		return 1
+	}
+
+	return 0
+}
+
+// setupEvents configures where structured events should be written. The
+// events file is returned, if one is used, so that it can be closed.
+func setupEvents(meta *config.Metadata) (*os.File, error) {
+	ui.Format = meta.LogFormat
+
+	var writers []io.Writer
+	if meta.LogFormat == ui.LogFormatJSON {
+		writers = append(writers, os.Stderr)
 	}
+
+	var events *os.File
+	if meta.EventsFile != "" {
+		f, err := os.Create(meta.EventsFile)
+		if err != nil {
+			return nil, err
+		}
+		events = f
+		writers = append(writers, f)
+	}
+
+	if len(writers) > 0 {
+		ui.Events = io.MultiWriter(writers...)
+	}
+
+	return events, nil
 }
 
 func gracefulRecover() {
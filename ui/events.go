@@ -0,0 +1,133 @@
+package ui
+
+import (
+	"encoding/json"
+	"io"
+	"os/exec"
+	"syscall"
+	"time"
+)
+
+// LogFormat describes the format of output from tusk itself.
+type LogFormat string
+
+const (
+	// LogFormatText means human-readable output
+	LogFormatText LogFormat = "text"
+	// LogFormatJSON means newline-delimited JSON events instead of text
+	LogFormatJSON LogFormat = "json"
+)
+
+// The types of events emitted.
+const (
+	EventTaskStart     = "task_start"
+	EventTaskFinish    = "task_finish"
+	EventCommandStart  = "command_start"
+	EventCommandFinish = "command_finish"
+	EventSkip          = "skip"
+	EventOption        = "option"
+	EventLog           = "log"
+)
+
+var (
+	// Format is the format used for output from tusk itself. Command output
+	// is not affected.
+	Format = LogFormatText
+
+	// Events is the writer that structured events are sent to. If nil,
+	// no events are written.
+	Events io.Writer
+)
+
+// Event is a single structured log entry.
+type Event struct {
+	Time     time.Time `json:"time"`
+	Type     string    `json:"type"`
+	Task     string    `json:"task,omitempty"`
+	Command  string    `json:"command,omitempty"`
+	Option   string    `json:"option,omitempty"`
+	Value    *string   `json:"value,omitempty"`
+	Status   Status    `json:"status,omitempty"`
+	ExitCode *int      `json:"exit_code,omitempty"`
+	Duration *float64  `json:"duration,omitempty"`
+	Reason   string    `json:"reason,omitempty"`
+	Level    string    `json:"level,omitempty"`
+	Message  string    `json:"message,omitempty"`
+}
+
+// EmitEvent writes an event as a single line of JSON.
+func EmitEvent(e Event) {
+	if Events == nil {
+		return
+	}
+
+	if e.Time.IsZero() {
+		e.Time = time.Now()
+	}
+
+	b, err := json.Marshal(e)
+	if err != nil {
+		return
+	}
+
+	Events.Write(append(b, '\n')) // nolint: errcheck, gas
+}
+
+// OptionEvaluated emits an event for the value an option was resolved to.
+func OptionEvaluated(name string, value string) {
+	EmitEvent(Event{Type: EventOption, Option: name, Value: &value})
+}
+
+func emitStart(r *Record) {
+	e := Event{Time: r.start, Type: EventTaskStart, Task: r.Task}
+	if r.Command != "" {
+		e.Type = EventCommandStart
+		e.Command = r.Command
+	}
+
+	EmitEvent(e)
+}
+
+func emitFinish(r *Record, err error) {
+	seconds := r.Duration.Seconds()
+	e := Event{
+		Type:     EventTaskFinish,
+		Task:     r.Task,
+		Status:   r.Status,
+		Duration: &seconds,
+	}
+
+	if r.Command != "" {
+		code := exitCode(err)
+		e.Type = EventCommandFinish
+		e.Command = r.Command
+		e.ExitCode = &code
+	}
+
+	EmitEvent(e)
+}
+
+func emitSkip(r *Record, reason string) {
+	EmitEvent(Event{
+		Type:    EventSkip,
+		Task:    r.Task,
+		Command: r.Command,
+		Status:  r.Status,
+		Reason:  reason,
+	})
+}
+
+// exitCode returns the exit code for an error returned by a command.
+func exitCode(err error) int {
+	if err == nil {
+		return 0
+	}
+
+	if exitErr, ok := err.(*exec.ExitError); ok {
+		if ws, ok := exitErr.Sys().(syscall.WaitStatus); ok {
+			return ws.ExitStatus()
+		}
+	}
+
+	return -1
+}
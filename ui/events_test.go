@@ -0,0 +1,105 @@
+package ui
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"testing"
+)
+
+func readEvents(t *testing.T, buf *bytes.Buffer) []Event {
+	var events []Event
+
+	scanner := bufio.NewScanner(buf)
+	for scanner.Scan() {
+		var e Event
+		if err := json.Unmarshal(scanner.Bytes(), &e); err != nil {
+			t.Fatalf("json.Unmarshal(%s): unexpected err: %s", scanner.Text(), err)
+		}
+		events = append(events, e)
+	}
+
+	return events
+}
+
+func TestEvents(t *testing.T) {
+	defer resetUIState()
+
+	buf := new(bytes.Buffer)
+	Events = buf
+
+	task := StartTask("mytask")
+	OptionEvaluated("foo", "")
+	command := StartCommand("mytask", "exit 1")
+	command.Finish(errors.New("oops"))
+	SkipCommand("mytask", "echo hello", "condition failed")
+	task.Finish(nil)
+
+	events := readEvents(t, buf)
+
+	expected := []string{
+		EventTaskStart,
+		EventOption,
+		EventCommandStart,
+		EventCommandFinish,
+		EventSkip,
+		EventTaskFinish,
+	}
+
+	if len(events) != len(expected) {
+		t.Fatalf("expected %d events, actual %d: %v", len(expected), len(events), events)
+	}
+
+	for i, e := range events {
+		if e.Type != expected[i] {
+			t.Errorf("event %d: expected type %s, actual %s", i, expected[i], e.Type)
+		}
+		if e.Time.IsZero() {
+			t.Errorf("event %d: expected time to be set", i)
+		}
+	}
+
+	if option := events[1]; option.Value == nil || *option.Value != "" {
+		t.Errorf("option event: expected empty value to be included, actual %v", option.Value)
+	}
+
+	if finish := events[3]; finish.ExitCode == nil || *finish.ExitCode != -1 {
+		t.Errorf("command finish event: expected exit code -1, actual %v", finish.ExitCode)
+	}
+
+	if skip := events[4]; skip.Reason != "condition failed" || skip.Status != StatusSkipped {
+		t.Errorf("skip event: unexpected reason or status: %+v", skip)
+	}
+
+	if finish := events[5]; finish.Status != StatusOK || finish.Duration == nil {
+		t.Errorf("task finish event: unexpected status or duration: %+v", finish)
+	}
+}
+
+func TestEvents_jsonFormat(t *testing.T) {
+	defer resetUIState()
+
+	stderr := new(bytes.Buffer)
+	LoggerStderr.SetOutput(stderr)
+
+	events := new(bytes.Buffer)
+	Events = events
+	Format = LogFormatJSON
+
+	PrintCommand("echo hello")
+	Warn("oops")
+
+	if stderr.Len() != 0 {
+		t.Errorf("expected no text output in json format, actual: %q", stderr.String())
+	}
+
+	actual := readEvents(t, events)
+	if len(actual) != 1 {
+		t.Fatalf("expected 1 event, actual %d: %v", len(actual), actual)
+	}
+
+	if actual[0].Type != EventLog || actual[0].Level != "warning" || actual[0].Message != "oops" {
+		t.Errorf("Warn(\"oops\"): unexpected event: %+v", actual[0])
+	}
+}
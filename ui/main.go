@@ -45,11 +45,13 @@ var (
 	// LoggerStderr is a logger that prints to stderr.
 	LoggerStderr = log.New(os.Stderr, "", 0)
 
-	bold   = conditionalColor(color.Bold)
-	blue   = conditionalColor(color.FgBlue)
-	cyan   = conditionalColor(color.FgCyan)
-	red    = conditionalColor(color.FgRed)
-	yellow = conditionalColor(color.FgYellow)
+	bold    = conditionalColor(color.Bold)
+	blue    = conditionalColor(color.FgBlue)
+	cyan    = conditionalColor(color.FgCyan)
+	green   = conditionalColor(color.FgGreen)
+	magenta = conditionalColor(color.FgMagenta)
+	red     = conditionalColor(color.FgRed)
+	yellow  = conditionalColor(color.FgYellow)
 )
 
 func println(l *log.Logger, v ...interface{}) {
@@ -61,7 +63,7 @@ func println(l *log.Logger, v ...interface{}) {
 }
 
 func printf(l *log.Logger, format string, v ...interface{}) {
-	if Verbosity == VerbosityLevelSilent {
+	if Verbosity == VerbosityLevelSilent || Format == LogFormatJSON {
 		return
 	}
 
@@ -11,6 +11,9 @@ func resetUIState() {
 	LoggerStdout.SetOutput(os.Stdout)
 	LoggerStderr.SetOutput(os.Stderr)
 	Verbosity = VerbosityLevelNormal
+	records = nil
+	Format = LogFormatText
+	Events = nil
 }
 
 type printTestCase struct {
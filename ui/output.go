@@ -2,6 +2,7 @@ package ui
 
 import (
 	"fmt"
+	"strings"
 )
 
 const (
@@ -53,6 +54,12 @@ func Error(a ...interface{}) {
 
 func logInStyle(title string, f formatter, a ...interface{}) {
 	message := fmt.Sprint(a...)
+	EmitEvent(Event{
+		Type:    EventLog,
+		Level:   strings.ToLower(title),
+		Message: message,
+	})
+
 	printf(
 		LoggerStderr,
 		logFormat,
@@ -0,0 +1,71 @@
+package ui
+
+import (
+	"bytes"
+	"hash/fnv"
+	"io"
+)
+
+// PrefixOutput is whether command output should be prefixed by task name.
+var PrefixOutput = false
+
+const prefixSeparator = " | "
+
+// prefixColors is the set of colors available for task prefixes.
+var prefixColors = []formatter{blue, cyan, green, magenta, yellow}
+
+// TaskPrefix returns the colorized prefix for output from a given task.
+// A task will always be assigned the same color.
+func TaskPrefix(taskName string) string {
+	h := fnv.New32a()
+	h.Write([]byte(taskName)) // nolint: errcheck, gas
+	f := prefixColors[h.Sum32()%uint32(len(prefixColors))]
+
+	return f(taskName) + prefixSeparator
+}
+
+// PrefixWriter is a line-buffered writer that prefixes each line of output.
+// Partial lines are held until a newline is written or Flush is called.
+type PrefixWriter struct {
+	w      io.Writer
+	prefix string
+	buf    bytes.Buffer
+}
+
+// NewPrefixWriter returns a PrefixWriter that writes to w.
+func NewPrefixWriter(w io.Writer, prefix string) *PrefixWriter {
+	return &PrefixWriter{w: w, prefix: prefix}
+}
+
+// Write writes every complete line buffered so far with the prefix.
+func (p *PrefixWriter) Write(b []byte) (int, error) {
+	p.buf.Write(b)
+
+	for {
+		i := bytes.IndexByte(p.buf.Bytes(), '\n')
+		if i < 0 {
+			break
+		}
+
+		if err := p.writeLine(p.buf.Next(i + 1)); err != nil {
+			return 0, err
+		}
+	}
+
+	return len(b), nil
+}
+
+// Flush writes any remaining partial line, terminated by a newline.
+func (p *PrefixWriter) Flush() error {
+	if p.buf.Len() == 0 {
+		return nil
+	}
+
+	line := append(p.buf.Next(p.buf.Len()), '\n')
+	return p.writeLine(line)
+}
+
+func (p *PrefixWriter) writeLine(line []byte) error {
+	_, err := p.w.Write(append([]byte(p.prefix), line...))
+	return err
+}
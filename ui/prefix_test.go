@@ -0,0 +1,50 @@
+package ui
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestPrefixWriter(t *testing.T) {
+	buf := new(bytes.Buffer)
+	w := NewPrefixWriter(buf, "foo | ")
+
+	for _, s := range []string{"one\ntw", "o\n", "three\nfour"} {
+		if _, err := w.Write([]byte(s)); err != nil {
+			t.Fatalf(`Write("%s"): unexpected err: %s`, s, err)
+		}
+	}
+
+	expected := "foo | one\nfoo | two\nfoo | three\n"
+	if actual := buf.String(); expected != actual {
+		t.Errorf("Write(): expected %q, actual %q", expected, actual)
+	}
+
+	if err := w.Flush(); err != nil {
+		t.Fatalf("Flush(): unexpected err: %s", err)
+	}
+
+	expected += "foo | four\n"
+	if actual := buf.String(); expected != actual {
+		t.Errorf("Flush(): expected %q, actual %q", expected, actual)
+	}
+}
+
+func TestTaskPrefix(t *testing.T) {
+	defer resetUIState()
+	Verbosity = VerbosityLevelQuiet
+
+	expected := "mytask" + prefixSeparator
+	if actual := TaskPrefix("mytask"); expected != actual {
+		t.Errorf(`TaskPrefix("mytask"): expected %q, actual %q`, expected, actual)
+	}
+
+	if TaskPrefix("mytask") != TaskPrefix("mytask") {
+		t.Error(`TaskPrefix("mytask"): prefix is not consistent`)
+	}
+
+	if !strings.HasSuffix(TaskPrefix("other"), prefixSeparator) {
+		t.Errorf(`TaskPrefix("other"): expected suffix %q`, prefixSeparator)
+	}
+}
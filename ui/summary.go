@@ -0,0 +1,137 @@
+package ui
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"text/tabwriter"
+	"time"
+)
+
+const summaryString = "Summary"
+
+// Status is the outcome of running a task or command.
+type Status string
+
+const (
+	// StatusOK means the task or command completed successfully
+	StatusOK Status = "ok"
+	// StatusFailed means the task or command returned an error
+	StatusFailed Status = "failed"
+	// StatusSkipped means the task or command was not run
+	StatusSkipped Status = "skipped"
+)
+
+// Record is the timing and outcome of a single task or command.
+type Record struct {
+	Task     string
+	Command  string
+	Status   Status
+	Duration time.Duration
+
+	start time.Time
+}
+
+// records is the list of tasks and commands run, in the order they started.
+var records []*Record
+
+// StartTask begins timing a task.
+func StartTask(taskName string) *Record {
+	return startRecord(&Record{Task: taskName})
+}
+
+// StartCommand begins timing a command run by a task.
+func StartCommand(taskName string, command string) *Record {
+	return startRecord(&Record{Task: taskName, Command: command})
+}
+
+func startRecord(r *Record) *Record {
+	r.start = time.Now()
+	records = append(records, r)
+	emitStart(r)
+	return r
+}
+
+// Finish stops timing and sets the status based on the error returned.
+func (r *Record) Finish(err error) {
+	r.Duration = time.Since(r.start)
+
+	r.Status = StatusOK
+	if err != nil {
+		r.Status = StatusFailed
+	}
+
+	emitFinish(r, err)
+}
+
+// SkipTask records a task that was not run and the reason why.
+func SkipTask(taskName string, reason string) {
+	skipRecord(&Record{Task: taskName}, reason)
+}
+
+// SkipCommand records a command that was not run and the reason why.
+func SkipCommand(taskName string, command string, reason string) {
+	skipRecord(&Record{Task: taskName, Command: command}, reason)
+}
+
+func skipRecord(r *Record, reason string) {
+	r.Status = StatusSkipped
+	records = append(records, r)
+	emitSkip(r, reason)
+}
+
+// ClearSummary forgets every task and command run so far, so that the next
+// summary only includes what runs after.
+func ClearSummary() {
+	records = nil
+}
+
+// PrintSummary prints the status and duration of every task run. In verbose
+// mode, individual commands are included as well.
+func PrintSummary() {
+	if Verbosity <= VerbosityLevelQuiet || len(records) == 0 {
+		return
+	}
+
+	verbose := Verbosity >= VerbosityLevelVerbose
+
+	buf := new(bytes.Buffer)
+	w := tabwriter.NewWriter(buf, 0, 0, 2, ' ', 0)
+
+	header := []string{"TASK", "STATUS", "DURATION"}
+	if verbose {
+		header = append(header, "COMMAND")
+	}
+	fmt.Fprintln(w, strings.Join(header, "\t")) // nolint: errcheck
+
+	for _, r := range records {
+		if r.Command != "" && !verbose {
+			continue
+		}
+
+		row := []string{r.Task, string(r.Status), r.Duration.Round(time.Millisecond).String()}
+		if verbose {
+			row = append(row, firstLine(r.Command))
+		}
+		fmt.Fprintln(w, strings.Join(row, "\t")) // nolint: errcheck
+	}
+
+	w.Flush() // nolint: errcheck, gas
+
+	printf(
+		LoggerStderr,
+		"[%s]\n%s",
+		blue(summaryString),
+		buf.String(),
+	)
+}
+
+// firstLine shortens multi-line commands to fit in a single row.
+func firstLine(command string) string {
+	lines := strings.SplitN(strings.TrimSpace(command), "\n", 2)
+	if len(lines) > 1 {
+		return lines[0] + " ..."
+	}
+
+	return lines[0]
+}
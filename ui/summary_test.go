@@ -0,0 +1,70 @@
+package ui
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+)
+
+func setRecords() {
+	records = []*Record{
+		{Task: "one", Status: StatusOK, Duration: 1500 * time.Millisecond},
+		{Task: "one", Command: "echo one", Status: StatusOK, Duration: time.Second},
+		{Task: "one", Command: "echo two\necho three", Status: StatusSkipped},
+		{Task: "two", Status: StatusFailed, Duration: 2 * time.Millisecond},
+	}
+}
+
+var summaryTests = []printTestCase{
+	{
+		`PrintSummary()`,
+		LoggerStderr,
+		func() { setRecords(); PrintSummary() },
+		VerbosityLevelQuiet,
+		VerbosityLevelNormal,
+		fmt.Sprintf("[%s]\n", summaryString) +
+			"TASK  STATUS  DURATION\n" +
+			"one   ok      1.5s\n" +
+			"two   failed  2ms\n",
+	},
+	{
+		`PrintSummary() verbose`,
+		LoggerStderr,
+		func() { setRecords(); PrintSummary() },
+		VerbosityLevelQuiet,
+		VerbosityLevelVerbose,
+		fmt.Sprintf("[%s]\n", summaryString) +
+			"TASK  STATUS   DURATION  COMMAND\n" +
+			"one   ok       1.5s      \n" +
+			"one   ok       1s        echo one\n" +
+			"one   skipped  0s        echo two ...\n" +
+			"two   failed   2ms       \n",
+	},
+}
+
+func TestPrintSummary(t *testing.T) {
+	for _, tt := range summaryTests {
+		testPrint(t, tt)
+	}
+}
+
+func TestRecord_Finish(t *testing.T) {
+	defer resetUIState()
+
+	ok := StartTask("mytask")
+	ok.Finish(nil)
+	if ok.Status != StatusOK {
+		t.Errorf("Finish(nil): expected status %s, actual %s", StatusOK, ok.Status)
+	}
+
+	failed := StartCommand("mytask", "exit 1")
+	failed.Finish(errors.New("exit status 1"))
+	if failed.Status != StatusFailed {
+		t.Errorf("Finish(err): expected status %s, actual %s", StatusFailed, failed.Status)
+	}
+
+	if len(records) != 2 || records[0] != ok || records[1] != failed {
+		t.Errorf("expected records to be kept in order started, actual %v", records)
+	}
+}
@@ -0,0 +1,184 @@
+package watch
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"syscall"
+	"unsafe"
+
+	"github.com/rliebz/tusk/ui"
+)
+
+const notifyMask = syscall.IN_CREATE | syscall.IN_DELETE | syscall.IN_MODIFY |
+	syscall.IN_MOVED_FROM | syscall.IN_MOVED_TO
+
+// notifier finds changes using inotify. Since inotify does not watch
+// directories recursively, every directory inside of the roots is watched,
+// including directories created later.
+type notifier struct {
+	fd    int
+	paths chan string
+	done  chan struct{}
+
+	mu     sync.Mutex
+	dirs   map[int32]string
+	closed bool
+}
+
+func newNotifier(roots []string) (source, error) {
+	fd, err := syscall.InotifyInit1(syscall.IN_CLOEXEC)
+	if err != nil {
+		return nil, os.NewSyscallError("inotify_init1", err)
+	}
+
+	n := &notifier{
+		fd:    fd,
+		paths: make(chan string),
+		done:  make(chan struct{}),
+		dirs:  make(map[int32]string),
+	}
+
+	for _, root := range roots {
+		if err := n.addTree(root); err != nil {
+			syscall.Close(fd) // nolint: errcheck, gas
+			return nil, err
+		}
+	}
+
+	go n.read()
+
+	return n, nil
+}
+
+func (n *notifier) Paths() <-chan string {
+	return n.paths
+}
+
+// Close stops watching. Removing the watches wakes up the blocked read, which
+// then closes the file descriptor.
+func (n *notifier) Close() error {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	if n.closed {
+		return nil
+	}
+
+	n.closed = true
+	close(n.done)
+	for wd := range n.dirs {
+		syscall.InotifyRmWatch(n.fd, uint32(wd)) // nolint: errcheck, gas
+	}
+
+	return nil
+}
+
+func (n *notifier) isClosed() bool {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	return n.closed
+}
+
+// addTree watches a directory and every directory inside of it.
+func (n *notifier) addTree(root string) error {
+	return walk(root, func(path string, info os.FileInfo) error {
+		if !info.IsDir() {
+			return nil
+		}
+
+		wd, err := syscall.InotifyAddWatch(n.fd, path, notifyMask)
+		if err != nil {
+			return os.NewSyscallError("inotify_add_watch", err)
+		}
+
+		n.mu.Lock()
+		n.dirs[int32(wd)] = path
+		n.mu.Unlock()
+
+		return nil
+	})
+}
+
+func (n *notifier) read() {
+	defer close(n.paths)
+	defer syscall.Close(n.fd) // nolint: errcheck
+
+	buf := make([]byte, 64*1024)
+	for {
+		count, err := syscall.Read(n.fd, buf)
+		if n.isClosed() {
+			return
+		}
+
+		if err == syscall.EINTR {
+			continue
+		}
+
+		if err != nil || count < syscall.SizeofInotifyEvent {
+			ui.Warn(fmt.Sprintf("Stopped watching for file changes: %v", err))
+			return
+		}
+
+		if !n.handle(buf[:count]) {
+			return
+		}
+	}
+}
+
+// handle sends the path of each event, returning false if the notifier was
+// closed instead.
+func (n *notifier) handle(buf []byte) bool {
+	for offset := 0; offset+syscall.SizeofInotifyEvent <= len(buf); {
+		event := (*syscall.InotifyEvent)(unsafe.Pointer(&buf[offset]))
+		nameStart := offset + syscall.SizeofInotifyEvent
+		offset = nameStart + int(event.Len)
+
+		if event.Mask&syscall.IN_Q_OVERFLOW != 0 {
+			if !n.send("") {
+				return false
+			}
+			continue
+		}
+
+		n.mu.Lock()
+		dir, ok := n.dirs[event.Wd]
+		if event.Mask&syscall.IN_IGNORED != 0 {
+			delete(n.dirs, event.Wd)
+		}
+		n.mu.Unlock()
+
+		if !ok || event.Mask&syscall.IN_IGNORED != 0 {
+			continue
+		}
+
+		name := strings.TrimRight(string(buf[nameStart:offset]), "\x00")
+		path := filepath.Join(dir, name)
+
+		isDir := event.Mask&syscall.IN_ISDIR != 0
+		if isDir && event.Mask&(syscall.IN_CREATE|syscall.IN_MOVED_TO) != 0 {
+			if err := n.addTree(path); err != nil {
+				ui.Warn(fmt.Sprintf("Could not watch %s: %s", path, err))
+			}
+		}
+
+		if !n.send(path) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// send sends a path, returning false if the notifier was closed instead.
+func (n *notifier) send(path string) bool {
+	select {
+	case n.paths <- path:
+		return true
+	case <-n.done:
+		return false
+	}
+}
@@ -0,0 +1,13 @@
+//go:build !linux
+// +build !linux
+
+package watch
+
+import (
+	"fmt"
+	"runtime"
+)
+
+func newNotifier(roots []string) (source, error) {
+	return nil, fmt.Errorf("inotify is not supported on %s", runtime.GOOS)
+}
@@ -0,0 +1,105 @@
+package watch
+
+import (
+	"os"
+	"sync"
+	"time"
+)
+
+// fileState is what is compared to tell if a file changed between polls.
+type fileState struct {
+	modTime time.Time
+	size    int64
+}
+
+// poller finds changes by checking every file on an interval.
+type poller struct {
+	roots []string
+	paths chan string
+	done  chan struct{}
+	once  sync.Once
+}
+
+func newPoller(roots []string) *poller {
+	p := &poller{
+		roots: roots,
+		paths: make(chan string),
+		done:  make(chan struct{}),
+	}
+
+	// The first scan is done before returning so that no changes are missed
+	go p.run(p.scan())
+
+	return p
+}
+
+func (p *poller) Paths() <-chan string {
+	return p.paths
+}
+
+func (p *poller) Close() error {
+	p.once.Do(func() { close(p.done) })
+	return nil
+}
+
+func (p *poller) run(previous map[string]fileState) {
+	defer close(p.paths)
+
+	ticker := time.NewTicker(PollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-p.done:
+			return
+		case <-ticker.C:
+		}
+
+		current := p.scan()
+
+		for path, state := range current {
+			if old, ok := previous[path]; ok && old == state {
+				continue
+			}
+			if !p.send(path) {
+				return
+			}
+		}
+
+		for path := range previous {
+			if _, ok := current[path]; ok {
+				continue
+			}
+			if !p.send(path) {
+				return
+			}
+		}
+
+		previous = current
+	}
+}
+
+// send sends a path, returning false if the poller was closed instead.
+func (p *poller) send(path string) bool {
+	select {
+	case p.paths <- path:
+		return true
+	case <-p.done:
+		return false
+	}
+}
+
+// scan returns the state of every file inside of the roots.
+func (p *poller) scan() map[string]fileState {
+	files := make(map[string]fileState)
+	for _, root := range p.roots {
+		walk(root, func(path string, info os.FileInfo) error { // nolint: errcheck
+			if !info.IsDir() {
+				files[path] = fileState{modTime: info.ModTime(), size: info.Size()}
+			}
+			return nil
+		})
+	}
+
+	return files
+}
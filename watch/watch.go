@@ -0,0 +1,243 @@
+// Package watch reports changes to the files matching a list of patterns.
+package watch
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/rliebz/tusk/ui"
+)
+
+// Debounce is how long to wait after a change for more changes, so that a
+// burst of changes, such as saving several files, is reported once.
+var Debounce = 200 * time.Millisecond
+
+// PollInterval is how often files are checked when inotify is unavailable.
+var PollInterval = 500 * time.Millisecond
+
+// skippedDirs are never watched, since they change often and are unlikely to
+// be relevant.
+var skippedDirs = map[string]bool{".git": true, ".hg": true, ".svn": true}
+
+// source reports the paths of files that may have changed. An empty path
+// means that changes may have been missed.
+type source interface {
+	Paths() <-chan string
+	Close() error
+}
+
+// Watcher reports changes to the files matching a list of patterns.
+type Watcher struct {
+	patterns []string
+	source   source
+	changes  chan []string
+	done     chan struct{}
+}
+
+// New starts watching the files matching a list of absolute glob patterns.
+// Patterns may use ** to match any number of directories, and a pattern that
+// matches a directory matches every file in it. Inotify is used where
+// available, with polling as a fallback.
+func New(patterns []string) (*Watcher, error) {
+	if len(patterns) == 0 {
+		return nil, fmt.Errorf("no files to watch")
+	}
+
+	roots := findRoots(patterns)
+
+	src, err := newNotifier(roots)
+	if err != nil {
+		ui.Debug(fmt.Sprintf("Polling for file changes: %s", err))
+		src = newPoller(roots)
+	}
+
+	w := &Watcher{
+		patterns: patterns,
+		source:   src,
+		changes:  make(chan []string),
+		done:     make(chan struct{}),
+	}
+	go w.debounce()
+
+	return w, nil
+}
+
+// Changes returns a channel that receives the paths changed after each burst
+// of changes has settled.
+func (w *Watcher) Changes() <-chan []string {
+	return w.changes
+}
+
+// Close stops watching for changes.
+func (w *Watcher) Close() error {
+	close(w.done)
+	return w.source.Close()
+}
+
+// debounce collects the matching paths changed until no more changes are seen
+// for the debounce period, then sends them all at once.
+func (w *Watcher) debounce() {
+	var changed []string
+	var settled <-chan time.Time
+
+	paths := w.source.Paths()
+	for {
+		select {
+		case <-w.done:
+			return
+		case path, ok := <-paths:
+			if !ok {
+				return
+			}
+			if !w.matches(path) {
+				continue
+			}
+			if path != "" && !contains(changed, path) {
+				changed = append(changed, path)
+			}
+			settled = time.After(Debounce)
+		case <-settled:
+			sort.Strings(changed)
+			select {
+			case w.changes <- changed:
+			case <-w.done:
+				return
+			}
+			changed = nil
+			settled = nil
+		}
+	}
+}
+
+func (w *Watcher) matches(path string) bool {
+	if path == "" {
+		return true
+	}
+
+	for _, pattern := range w.patterns {
+		if Match(pattern, path) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func contains(items []string, item string) bool {
+	for _, i := range items {
+		if i == item {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Match tells if a path matches a glob pattern. A ** in the pattern matches
+// any number of directories, and a pattern that matches a directory matches
+// every path inside of it.
+func Match(pattern, path string) bool {
+	return matchParts(splitPath(pattern), splitPath(path))
+}
+
+func matchParts(pattern, path []string) bool {
+	for len(pattern) > 0 {
+		if pattern[0] == "**" {
+			for i := 0; i <= len(path); i++ {
+				if matchParts(pattern[1:], path[i:]) {
+					return true
+				}
+			}
+			return false
+		}
+
+		if len(path) == 0 {
+			return false
+		}
+
+		if ok, err := filepath.Match(pattern[0], path[0]); !ok || err != nil {
+			return false
+		}
+
+		pattern, path = pattern[1:], path[1:]
+	}
+
+	return true
+}
+
+func splitPath(path string) []string {
+	return strings.Split(filepath.ToSlash(filepath.Clean(path)), "/")
+}
+
+// findRoots returns the directories to watch for a list of patterns, which is
+// the nearest existing directory above the first wildcard of each pattern.
+func findRoots(patterns []string) []string {
+	var roots []string
+	for _, pattern := range patterns {
+		root := pattern
+		if i := strings.IndexAny(root, "*?["); i >= 0 {
+			root = filepath.Dir(root[:i+1])
+		}
+
+		for {
+			if info, err := os.Stat(root); err == nil && info.IsDir() {
+				break
+			}
+
+			parent := filepath.Dir(root)
+			if parent == root {
+				break
+			}
+			root = parent
+		}
+
+		roots = append(roots, root)
+	}
+
+	// A directory is a prefix of everything inside it, so it always sorts first
+	sort.Strings(roots)
+
+	var unique []string
+candidates:
+	for _, root := range roots {
+		for _, dir := range unique {
+			if isWithin(root, dir) {
+				continue candidates
+			}
+		}
+		unique = append(unique, root)
+	}
+
+	return unique
+}
+
+// isWithin tells if a path is the same as or inside of a directory.
+func isWithin(path, dir string) bool {
+	rel, err := filepath.Rel(dir, path)
+	if err != nil {
+		return false
+	}
+
+	return rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
+
+// walk calls a function for every file and directory inside of a root,
+// skipping version control directories. Files that cannot be read are
+// skipped, since they may have been removed during the walk.
+func walk(root string, f func(path string, info os.FileInfo) error) error {
+	return filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return nil
+		}
+
+		if info.IsDir() && path != root && skippedDirs[info.Name()] {
+			return filepath.SkipDir
+		}
+
+		return f(path, info)
+	})
+}
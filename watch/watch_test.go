@@ -0,0 +1,136 @@
+package watch
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+var matchtests = []struct {
+	pattern  string
+	path     string
+	expected bool
+}{
+	{"/src/*.go", "/src/main.go", true},
+	{"/src/*.go", "/src/main.txt", false},
+	{"/src/*.go", "/src/pkg/main.go", false},
+	{"/src/**/*.go", "/src/main.go", true},
+	{"/src/**/*.go", "/src/a/b/main.go", true},
+	{"/src/**", "/src/a/b/main.go", true},
+	{"/src", "/src/a/main.go", true},
+	{"/src", "/srcs/main.go", false},
+	{"/src/a?.go", "/src/ab.go", true},
+	{"/src/[ab].go", "/src/c.go", false},
+}
+
+func TestMatch(t *testing.T) {
+	for _, tt := range matchtests {
+		if actual := Match(tt.pattern, tt.path); tt.expected != actual {
+			t.Errorf(
+				"Match(%q, %q): expected %t, actual %t",
+				tt.pattern, tt.path, tt.expected, actual,
+			)
+		}
+	}
+}
+
+func TestFindRoots(t *testing.T) {
+	dir, err := ioutil.TempDir("", "tusk-watch")
+	if err != nil {
+		t.Fatalf("unexpected err: %s", err)
+	}
+	defer os.RemoveAll(dir) // nolint: errcheck
+
+	src := filepath.Join(dir, "src")
+	if err = os.Mkdir(src, 0700); err != nil {
+		t.Fatalf("unexpected err: %s", err)
+	}
+
+	patterns := []string{
+		filepath.Join(src, "**", "*.go"),
+		filepath.Join(src, "pkg", "*.go"),
+		filepath.Join(dir, "missing", "*.txt"),
+		filepath.Join(dir, "other.yml"),
+	}
+
+	expected := []string{dir}
+	if actual := findRoots(patterns); !reflect.DeepEqual(expected, actual) {
+		t.Errorf("findRoots(): expected %v, actual %v", expected, actual)
+	}
+
+	expected = []string{src}
+	if actual := findRoots(patterns[:2]); !reflect.DeepEqual(expected, actual) {
+		t.Errorf("findRoots(): expected %v, actual %v", expected, actual)
+	}
+}
+
+func TestWatcher(t *testing.T) {
+	testWatcher(t, func(patterns []string) (*Watcher, error) {
+		return New(patterns)
+	})
+}
+
+func TestWatcher_polling(t *testing.T) {
+	defer func(interval time.Duration) { PollInterval = interval }(PollInterval)
+	PollInterval = 20 * time.Millisecond
+
+	testWatcher(t, func(patterns []string) (*Watcher, error) {
+		w := &Watcher{
+			patterns: patterns,
+			source:   newPoller(findRoots(patterns)),
+			changes:  make(chan []string),
+			done:     make(chan struct{}),
+		}
+		go w.debounce()
+		return w, nil
+	})
+}
+
+func testWatcher(t *testing.T, create func([]string) (*Watcher, error)) {
+	defer func(debounce time.Duration) { Debounce = debounce }(Debounce)
+	Debounce = 100 * time.Millisecond
+
+	dir, err := ioutil.TempDir("", "tusk-watch")
+	if err != nil {
+		t.Fatalf("unexpected err: %s", err)
+	}
+	defer os.RemoveAll(dir) // nolint: errcheck
+
+	w, err := create([]string{filepath.Join(dir, "**", "*.go")})
+	if err != nil {
+		t.Fatalf("New(): unexpected err: %s", err)
+	}
+	defer w.Close() // nolint: errcheck
+
+	sub := filepath.Join(dir, "sub")
+	if err = os.Mkdir(sub, 0700); err != nil {
+		t.Fatalf("unexpected err: %s", err)
+	}
+
+	// Give the new directory time to be watched before writing to it
+	time.Sleep(50 * time.Millisecond)
+
+	files := []string{
+		filepath.Join(dir, "a.go"),
+		filepath.Join(dir, "ignored.txt"),
+		filepath.Join(sub, "b.go"),
+	}
+	for _, file := range files {
+		if err = ioutil.WriteFile(file, []byte("package main"), 0600); err != nil {
+			t.Fatalf("unexpected err: %s", err)
+		}
+	}
+
+	expected := []string{files[0], files[2]}
+	select {
+	case actual := <-w.Changes():
+		if !reflect.DeepEqual(expected, actual) {
+			t.Errorf("Watcher.Changes(): expected %v, actual %v", expected, actual)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Watcher.Changes(): timed out waiting for changes")
+	}
+}